@@ -0,0 +1,155 @@
+package kop2cup
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+/*
+ISO 8601 の期間（例: "PT1H30M", "P1DT12H", "P2W"）です。年・月は期間が一定でないため対象外です。
+*/
+var isoDurationPattern = regexp.MustCompile(`^(-)?P(?:([0-9.]+)W)?(?:([0-9.]+)D)?(?:T(?:([0-9.]+)H)?(?:([0-9.]+)M)?(?:([0-9.]+)S)?)?$`)
+
+/*
+タグ kopcup-duration の値を時間の単位に変換します。
+*/
+func strToDurationUnit(str string) (time.Duration, error) {
+	switch str {
+	case "h":
+		return time.Hour, nil
+	case "m":
+		return time.Minute, nil
+	case "s":
+		return time.Second, nil
+	case "ms":
+		return time.Millisecond, nil
+	case "us", "µs", "μs":
+		return time.Microsecond, nil
+	case "ns":
+		return time.Nanosecond, nil
+	}
+	return 0, errors.New("convert error: invalid kopcup-duration " + str)
+}
+
+/*
+time.Duration と数値の変換で使う単位を返します。未指定の場合は秒です。
+*/
+func (c *config) durationUnit() time.Duration {
+	if c.duration <= 0 {
+		return time.Second
+	}
+	return c.duration
+}
+
+/*
+time.Duration 同士ではない time.Duration <-> 文字列・整数・浮動小数点数の変換であれば true を返します。
+*/
+func isDurationConversion(destType reflect.Type, srcType reflect.Type) bool {
+	durationType := reflect.TypeOf(time.Duration(0))
+	if (destType == durationType) == (srcType == durationType) {
+		return false
+	}
+	other := srcType
+	if srcType == durationType {
+		other = destType
+	}
+	switch other.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+/*
+time.Duration に変換します。
+  - 文字列は time.ParseDuration の形式（"90s", "1h30m"）、ISO 8601 の期間（"PT1H30M"）、または数字のみ（unit 単位）
+  - 整数・浮動小数点数は unit 単位
+*/
+func convertToDuration(srcField reflect.Value, unit time.Duration) (time.Duration, error) {
+	switch srcField.Type().Kind() {
+	case reflect.String:
+		return parseDuration(srcField.String(), unit)
+	case reflect.Float32, reflect.Float64:
+		return floatToDuration(srcField.Float(), unit)
+	}
+	i, err := convertToInt(srcField)
+	if err != nil {
+		return 0, err
+	}
+	if i > math.MaxInt64/int64(unit) || i < math.MinInt64/int64(unit) {
+		return 0, fmt.Errorf("%w: %d%s to time.Duration", ErrOverflow, i, unit)
+	}
+	return time.Duration(i) * unit, nil
+}
+
+/*
+time.Duration を destType（文字列・整数・浮動小数点数）の値に変換します。
+  - 文字列は time.Duration.String の形式（"1h30m0s"）
+  - 整数は unit 単位（unit 未満の端数はエラー）、浮動小数点数は unit 単位の小数
+*/
+func convertFromDuration(d time.Duration, destType reflect.Type, unit time.Duration) (reflect.Value, error) {
+	switch destType.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(d.String()), nil
+	case reflect.Float32, reflect.Float64:
+		return reflect.ValueOf(float64(d) / float64(unit)), nil
+	}
+	if d%unit != 0 {
+		return reflect.Value{}, fmt.Errorf("%w: %s to %s units", ErrTruncated, d, unit)
+	}
+	return reflect.ValueOf(int64(d / unit)), nil
+}
+
+func floatToDuration(f float64, unit time.Duration) (time.Duration, error) {
+	ns := math.Round(f * float64(unit))
+	if math.IsNaN(ns) || ns < math.MinInt64 || ns >= math.MaxInt64 {
+		return 0, fmt.Errorf("%w: %g%s to time.Duration", ErrOverflow, f, unit)
+	}
+	return time.Duration(ns), nil
+}
+
+/*
+文字列を time.Duration に変換します。
+*/
+func parseDuration(str string, unit time.Duration) (time.Duration, error) {
+	if d, err := time.ParseDuration(str); err == nil {
+		return d, nil
+	}
+	if f, err := strconv.ParseFloat(str, 64); err == nil {
+		return floatToDuration(f, unit)
+	}
+	m := isoDurationPattern.FindStringSubmatch(str)
+	if m == nil || str == "P" || str == "-P" || str[len(str)-1] == 'T' {
+		return 0, fmt.Errorf("convert error: invalid duration %q", str)
+	}
+	var d time.Duration
+	for i, u := range []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		if m[i+2] == "" {
+			continue
+		}
+		f, err := strconv.ParseFloat(m[i+2], 64)
+		if err != nil {
+			return 0, fmt.Errorf("convert error: invalid duration %q", str)
+		}
+		v, err := floatToDuration(f, u)
+		if err != nil {
+			return 0, err
+		}
+		if d > math.MaxInt64-v {
+			return 0, fmt.Errorf("%w: %s to time.Duration", ErrOverflow, str)
+		}
+		d += v
+	}
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
@@ -0,0 +1,168 @@
+package kop2cup
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	testCases := []struct {
+		Input      string
+		Expected   time.Duration
+		ShouldFail bool
+	}{
+		{Input: "90s", Expected: 90 * time.Second},
+		{Input: "1h30m", Expected: 90 * time.Minute},
+		{Input: "PT1H30M", Expected: 90 * time.Minute},
+		{Input: "PT0.5S", Expected: 500 * time.Millisecond},
+		{Input: "P1DT12H", Expected: 36 * time.Hour},
+		{Input: "P2W", Expected: 14 * 24 * time.Hour},
+		{Input: "-PT15M", Expected: -15 * time.Minute},
+		{Input: "90", Expected: 90 * time.Second},
+		{Input: "1.5", Expected: 1500 * time.Millisecond},
+		{Input: "P", ShouldFail: true},
+		{Input: "PT", ShouldFail: true},
+		{Input: "P1Y", ShouldFail: true},
+		{Input: "P1.2.3D", ShouldFail: true},
+		{Input: "soon", ShouldFail: true},
+		{Input: "P1000000W", ShouldFail: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Input, func(t *testing.T) {
+			result, err := parseDuration(tc.Input, time.Second)
+			if tc.ShouldFail {
+				if err == nil {
+					t.Errorf("Expected an error, but got %v", result)
+				}
+				return
+			}
+			if err != nil || result != tc.Expected {
+				t.Errorf("Unexpected result. Got: %v (%v), Expected: %v", result, err, tc.Expected)
+			}
+		})
+	}
+}
+
+type SourceDuration struct {
+	Text    string
+	ISO     string
+	Seconds int
+	Millis  int64   `kopcup-duration:"ms"`
+	Minutes float64 `kopcup-duration:"m"`
+}
+
+type DestinationDuration struct {
+	Text    time.Duration
+	ISO     time.Duration
+	Seconds time.Duration
+	Millis  time.Duration
+	Minutes time.Duration
+}
+
+func TestCopyFromDuration(t *testing.T) {
+	var dest DestinationDuration
+	src := SourceDuration{Text: "1h30m", ISO: "PT1H30M", Seconds: 90, Millis: 1500, Minutes: 1.5}
+	if err := CopyFrom(&dest, &src); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	expected := DestinationDuration{Text: 90 * time.Minute, ISO: 90 * time.Minute, Seconds: 90 * time.Second, Millis: 1500 * time.Millisecond, Minutes: 90 * time.Second}
+	if dest != expected {
+		t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", dest, expected)
+	}
+
+	var back struct {
+		Text    string
+		Seconds int
+		Millis  int64   `kopcup-duration:"ms"`
+		Minutes float64 `kopcup-duration:"m"`
+	}
+	src2 := struct {
+		Text    time.Duration
+		Seconds time.Duration
+		Millis  time.Duration `kopcup-duration:"ms"`
+		Minutes time.Duration `kopcup-duration:"m"`
+	}{Text: 90 * time.Minute, Seconds: 90 * time.Second, Millis: 1500 * time.Millisecond, Minutes: 90 * time.Second}
+	if err := CopyFrom(&back, &src2); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if back.Text != "1h30m0s" || back.Seconds != 90 || back.Millis != 1500 || back.Minutes != 1.5 {
+		t.Errorf("Unexpected result. Got: %+v", back)
+	}
+}
+
+func TestCopyFromDurationDestinationTag(t *testing.T) {
+	d := 90 * time.Second
+
+	var millis struct {
+		D int64   `kopcup-duration:"ms"`
+		F float64 `kopcup-duration:"m"`
+	}
+	if err := CopyFrom(&millis, &struct{ D, F time.Duration }{D: d, F: d}); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if millis.D != 90000 || millis.F != 1.5 {
+		t.Errorf("Unexpected result. Got: %+v", millis)
+	}
+
+	var back struct{ D, F time.Duration }
+	if err := CopyFrom(&back, &millis); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if back.D != d || back.F != d {
+		t.Errorf("Unexpected result. Got: %+v", back)
+	}
+
+	var durationTagged struct {
+		D time.Duration `kopcup-duration:"ms"`
+	}
+	if err := CopyFrom(&durationTagged, &struct{ D int }{D: 90000}); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if durationTagged.D != d {
+		t.Errorf("Unexpected result. Got: %v, Expected: %v", durationTagged.D, d)
+	}
+}
+
+func TestCopyFromDurationError(t *testing.T) {
+	testCases := []struct {
+		Name    string
+		Src     interface{}
+		Dest    interface{}
+		Options []Option
+		Err     error
+	}{
+		{Name: "Truncated", Src: &struct{ D time.Duration }{D: 1500 * time.Millisecond}, Dest: &struct{ D int }{}, Err: ErrTruncated},
+		{Name: "Overflow", Src: &struct{ D int64 }{D: 1 << 62}, Dest: &struct{ D time.Duration }{}, Err: ErrOverflow},
+		{Name: "DestOverflow", Src: &struct{ D time.Duration }{D: time.Hour}, Dest: &struct{ D int8 }{}, Options: []Option{WithDurationUnit(time.Second)}, Err: ErrOverflow},
+		{Name: "SignLoss", Src: &struct{ D time.Duration }{D: -time.Second}, Dest: &struct{ D uint }{}, Err: ErrSignLoss},
+		{Name: "InvalidTag", Src: &struct {
+			D int `kopcup-duration:"day"`
+		}{D: 1}, Dest: &struct{ D time.Duration }{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := CopyFrom(tc.Dest, tc.Src, tc.Options...)
+			if err == nil {
+				t.Fatal("Expected an error, but got none.")
+			}
+			if tc.Err != nil && !errors.Is(err, tc.Err) {
+				t.Errorf("Unexpected error. Got: %v, Expected: %v", err, tc.Err)
+			}
+		})
+	}
+}
+
+func TestCopyFromMapDuration(t *testing.T) {
+	var dest DestinationDuration
+	if err := CopyFromMap(&dest, map[string]interface{}{"Text": "PT2M", "Seconds": 30, "Minutes": 2.5}, WithDurationUnit(time.Minute)); err != nil {
+		t.Fatalf("CopyFromMap failed: %v", err)
+	}
+	expected := DestinationDuration{Text: 2 * time.Minute, Seconds: 30 * time.Minute, Minutes: 150 * time.Second}
+	if !reflect.DeepEqual(dest, expected) {
+		t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", dest, expected)
+	}
+}
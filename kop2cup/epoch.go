@@ -0,0 +1,97 @@
+package kop2cup
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+タグ kopcup-epoch の値を Unix 時間の単位に変換します。
+*/
+func strToEpochUnit(str string) (time.Duration, error) {
+	switch str {
+	case "s":
+		return time.Second, nil
+	case "ms":
+		return time.Millisecond, nil
+	case "us", "µs", "μs":
+		return time.Microsecond, nil
+	case "ns":
+		return time.Nanosecond, nil
+	}
+	return 0, errors.New("convert error: invalid kopcup-epoch " + str)
+}
+
+/*
+time.Time と数値の変換で使う Unix 時間の単位を返します。未指定の場合は秒です。
+*/
+func (c *config) epochUnit() time.Duration {
+	if c.epoch <= 0 {
+		return time.Second
+	}
+	return c.epoch
+}
+
+/*
+unit 単位の Unix 時間を time.Time に変換します。
+*/
+func epochToTime(v int64, unit time.Duration) time.Time {
+	if unit >= time.Second {
+		return time.Unix(v*int64(unit/time.Second), 0)
+	}
+	k := int64(time.Second / unit)
+	return time.Unix(v/k, v%k*int64(unit))
+}
+
+/*
+unit 単位の Unix 時間（小数部を含む）を time.Time に変換します。
+*/
+func epochFloatToTime(f float64, unit time.Duration) (time.Time, error) {
+	sec := f * unit.Seconds()
+	if math.IsNaN(sec) || sec < math.MinInt64 || sec >= math.MaxInt64 {
+		return time.Time{}, fmt.Errorf("%w: %g to time.Time", ErrOverflow, f)
+	}
+	whole, frac := math.Modf(sec)
+	return time.Unix(int64(whole), int64(math.Round(frac*1e9))), nil
+}
+
+/*
+time.Time を unit 単位の Unix 時間に変換します。unit 未満は切り捨てます。
+*/
+func timeToEpoch(t time.Time, unit time.Duration) int64 {
+	if unit >= time.Second {
+		return t.Unix() / int64(unit/time.Second)
+	}
+	k := int64(time.Second / unit)
+	return t.Unix()*k + int64(t.Nanosecond())/int64(unit)
+}
+
+/*
+time.Time を unit 単位の Unix 時間（小数部を含む）に変換します。
+*/
+func timeToEpochFloat(t time.Time, unit time.Duration) float64 {
+	return (float64(t.Unix()) + float64(t.Nanosecond())/1e9) / unit.Seconds()
+}
+
+/*
+数字のみの文字列を unit 単位の Unix 時間として time.Time に変換します。
+数値でなければ ok は false です。
+*/
+func parseEpoch(str string, unit time.Duration) (t time.Time, ok bool, err error) {
+	if i, err := strconv.ParseInt(str, 10, 64); err == nil {
+		return epochToTime(i, unit), true, nil
+	}
+	if !strings.ContainsAny(str, ".eE") {
+		return time.Time{}, false, nil
+	}
+	f, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return time.Time{}, false, nil
+	}
+	t, err = epochFloatToTime(f, unit)
+	return t, true, err
+}
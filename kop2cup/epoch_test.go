@@ -0,0 +1,199 @@
+package kop2cup
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStrToEpochUnit(t *testing.T) {
+	testCases := []struct {
+		Input      string
+		Expected   time.Duration
+		ShouldFail bool
+	}{
+		{Input: "s", Expected: time.Second},
+		{Input: "ms", Expected: time.Millisecond},
+		{Input: "us", Expected: time.Microsecond},
+		{Input: "µs", Expected: time.Microsecond},
+		{Input: "ns", Expected: time.Nanosecond},
+		{Input: "m", ShouldFail: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Input, func(t *testing.T) {
+			result, err := strToEpochUnit(tc.Input)
+			if tc.ShouldFail {
+				if err == nil {
+					t.Error("Expected an error, but got none.")
+				}
+				return
+			}
+			if err != nil || result != tc.Expected {
+				t.Errorf("Unexpected result. Got: %v (%v), Expected: %v", result, err, tc.Expected)
+			}
+		})
+	}
+}
+
+func TestEpochRoundTrip(t *testing.T) {
+	tm := time.Date(2024, 4, 1, 9, 30, 15, 123456789, time.UTC)
+	testCases := []struct {
+		Unit     time.Duration
+		Epoch    int64
+		Expected time.Time
+	}{
+		{Unit: time.Second, Epoch: 1711963815, Expected: tm.Truncate(time.Second)},
+		{Unit: time.Millisecond, Epoch: 1711963815123, Expected: tm.Truncate(time.Millisecond)},
+		{Unit: time.Microsecond, Epoch: 1711963815123456, Expected: tm.Truncate(time.Microsecond)},
+		{Unit: time.Nanosecond, Epoch: 1711963815123456789, Expected: tm},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Unit.String(), func(t *testing.T) {
+			if result := timeToEpoch(tm, tc.Unit); result != tc.Epoch {
+				t.Errorf("Unexpected epoch. Got: %v, Expected: %v", result, tc.Epoch)
+			}
+			if result := epochToTime(tc.Epoch, tc.Unit); !result.Equal(tc.Expected) {
+				t.Errorf("Unexpected time. Got: %v, Expected: %v", result, tc.Expected)
+			}
+		})
+	}
+
+	if result := epochToTime(-1500, time.Millisecond); !result.Equal(time.Unix(-2, 500000000)) {
+		t.Errorf("Unexpected time for negative epoch. Got: %v", result)
+	}
+	if _, err := epochFloatToTime(1e300, time.Second); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Expected ErrOverflow, but got %v", err)
+	}
+}
+
+type SourceEpoch struct {
+	Seconds int64
+	Millis  int64   `kopcup-epoch:"ms"`
+	Float   float64 `kopcup-epoch:"s"`
+	Text    string  `kopcup-epoch:"ms"`
+}
+
+type DestinationEpoch struct {
+	Seconds time.Time
+	Millis  time.Time
+	Float   time.Time
+	Text    time.Time
+}
+
+type SourceEpochTime struct {
+	Seconds time.Time
+	Millis  time.Time `kopcup-epoch:"ms"`
+	Float   time.Time
+	Text    time.Time `kopcup-epoch:"ms"`
+}
+
+func TestCopyFromEpoch(t *testing.T) {
+	tm := time.Date(2024, 4, 1, 9, 30, 15, 250000000, time.UTC)
+
+	var dest DestinationEpoch
+	src := SourceEpoch{Seconds: 1711963815, Millis: 1711963815250, Float: 1711963815.25, Text: "1711963815250"}
+	if err := CopyFrom(&dest, &src); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if !dest.Seconds.Equal(tm.Truncate(time.Second)) || !dest.Millis.Equal(tm) || !dest.Float.Equal(tm) || !dest.Text.Equal(tm) {
+		t.Errorf("Unexpected result. Got: %+v", dest)
+	}
+
+	var back SourceEpoch
+	if err := CopyFrom(&back, &SourceEpochTime{Seconds: tm, Millis: tm, Float: tm, Text: tm}); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if expected := (SourceEpoch{Seconds: 1711963815, Millis: 1711963815250, Float: 1711963815.25, Text: "1711963815250"}); back != expected {
+		t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", back, expected)
+	}
+}
+
+func TestCopyFromEpochDestinationTag(t *testing.T) {
+	tm := time.Unix(1700000000, 123e6)
+
+	var millis struct {
+		T int64 `kopcup-epoch:"ms"`
+	}
+	if err := CopyFrom(&millis, &struct{ T time.Time }{T: tm}); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if millis.T != 1700000000123 {
+		t.Errorf("Unexpected result. Got: %d, Expected: %d", millis.T, int64(1700000000123))
+	}
+
+	var back struct{ T time.Time }
+	if err := CopyFrom(&back, &millis); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if !back.T.Equal(tm) {
+		t.Errorf("Unexpected result. Got: %v, Expected: %v", back.T, tm)
+	}
+
+	var timeTagged struct {
+		T time.Time `kopcup-epoch:"ms"`
+	}
+	if err := CopyFrom(&timeTagged, &struct{ T int64 }{T: 1700000000123}); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if !timeTagged.T.Equal(tm) {
+		t.Errorf("Unexpected result. Got: %v, Expected: %v", timeTagged.T, tm)
+	}
+
+	// コピー元・コピー先の両方に指定がある場合はコピー元が優先
+	var seconds struct {
+		T int64 `kopcup-epoch:"ms"`
+	}
+	src := struct {
+		T time.Time `kopcup-epoch:"s"`
+	}{T: time.Unix(1700000000, 0)}
+	if err := CopyFrom(&seconds, &src); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if seconds.T != 1700000000 {
+		t.Errorf("Unexpected result. Got: %d, Expected: %d", seconds.T, int64(1700000000))
+	}
+}
+
+func TestCopyFromEpochOption(t *testing.T) {
+	tm := time.Date(2024, 4, 1, 9, 30, 15, 0, time.UTC)
+	var dest struct{ At time.Time }
+	if err := CopyFrom(&dest, &struct{ At string }{At: "1711963815000000"}, WithEpochUnit(time.Microsecond)); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if !dest.At.Equal(tm) {
+		t.Errorf("Unexpected result. Got: %v, Expected: %v", dest.At, tm)
+	}
+
+	var back struct{ At uint32 }
+	if err := CopyFrom(&back, &struct{ At time.Time }{At: tm}, WithEpochUnit(time.Millisecond)); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Expected ErrOverflow, but got %v", err)
+	}
+
+	var before struct{ At uint64 }
+	if err := CopyFrom(&before, &struct{ At time.Time }{At: time.Unix(-1, 0)}); !errors.Is(err, ErrSignLoss) {
+		t.Errorf("Expected ErrSignLoss, but got %v", err)
+	}
+
+	src := struct {
+		At int `kopcup-epoch:"minutes"`
+	}{}
+	if err := CopyFrom(&dest, &src); err == nil {
+		t.Error("Expected an error for invalid kopcup-epoch, but got none.")
+	}
+}
+
+func TestCopyToMapEpoch(t *testing.T) {
+	tm := time.Date(2024, 4, 1, 9, 30, 15, 250000000, time.UTC)
+	dest := map[string]interface{}{}
+	if err := CopyToMap(dest, &SourceEpochTime{Millis: tm, Seconds: tm}); err != nil {
+		t.Fatalf("CopyToMap failed: %v", err)
+	}
+	if dest["Millis"] != int64(1711963815250) {
+		t.Errorf("Unexpected Millis. Got: %#v", dest["Millis"])
+	}
+	if dest["Seconds"] != tm {
+		t.Errorf("Unexpected Seconds. Got: %#v", dest["Seconds"])
+	}
+}
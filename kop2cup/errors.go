@@ -0,0 +1,118 @@
+package kop2cup
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	ErrInvalidArgument = errors.New("copy error: invalid argument")
+	ErrUnsupported     = errors.New("convert error: unsupported conversion")
+	ErrNilPointer      = errors.New("convert error: nil pointer")
+	ErrOverflow        = errors.New("convert error: value overflows destination type")
+	ErrSignLoss        = errors.New("convert error: negative value to unsigned type")
+	ErrTruncated       = errors.New("convert error: fractional part would be truncated")
+	ErrUnmatched       = errors.New("copy error: no matching destination field")
+	ErrUnexported      = errors.New("copy error: field is not exported")
+	ErrAliasConflict   = errors.New("copy error: conflicting kopcup-alias")
+	ErrAmbiguousName   = errors.New("copy error: ambiguous field name")
+)
+
+/*
+項目のコピーに失敗した場合のエラーです。errors.As で取り出せます。
+  - Path 項目のパス（例: "Contact.Address.City", "Items[1].Price"）
+  - SrcType コピー元の型
+  - DestType コピー先の型
+  - Value 変換できなかった値（参照できない場合は nil）
+  - Err 原因となったエラー
+*/
+type FieldError struct {
+	Path     string
+	SrcType  reflect.Type
+	DestType reflect.Type
+	Value    interface{}
+	Err      error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("field %s: cannot copy %v (%v) to %v: %v", e.Path, e.Value, e.SrcType, e.DestType, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+/*
+CollectAll で失敗した全ての項目のエラーです。
+errors.Is / errors.As は含まれる各 *FieldError に対して判定されます。
+*/
+type FieldErrors []*FieldError
+
+func (e FieldErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return fmt.Sprintf("%d fields failed: %s", len(e), strings.Join(msgs, "; "))
+}
+
+func (e FieldErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, fe := range e {
+		errs[i] = fe
+	}
+	return errs
+}
+
+/*
+項目のコピーで発生したエラーを FieldError にします。
+ネストした項目の FieldError（FieldErrors）であれば、パスの先頭に name を付け加えます。
+*/
+func newFieldError(name string, destType reflect.Type, srcField reflect.Value, err error) error {
+	switch e := err.(type) {
+	case *FieldError:
+		e.Path = joinPath(name, e.Path)
+		return e
+	case FieldErrors:
+		for _, fe := range e {
+			fe.Path = joinPath(name, fe.Path)
+		}
+		return e
+	}
+	fe := &FieldError{Path: name, DestType: destType, Err: err}
+	if srcField.IsValid() {
+		fe.SrcType = srcField.Type()
+		if srcField.CanInterface() {
+			fe.Value = srcField.Interface()
+		}
+	}
+	return fe
+}
+
+/*
+FieldError または FieldErrors を list に追加します。
+*/
+func appendFieldErrors(list FieldErrors, err error) FieldErrors {
+	switch e := err.(type) {
+	case *FieldError:
+		return append(list, e)
+	case FieldErrors:
+		return append(list, e...)
+	}
+	return append(list, &FieldError{Err: err})
+}
+
+/*
+項目のパスを連結します。添字（"[1]"）の前には "." を付けません。
+*/
+func joinPath(parent string, child string) string {
+	if parent == "" {
+		return child
+	}
+	if strings.HasPrefix(child, "[") {
+		return parent + child
+	}
+	return parent + "." + child
+}
@@ -0,0 +1,162 @@
+package kop2cup
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+type SourceError struct {
+	Name    string
+	Age     string
+	Items   []SrcItem
+	Contact SrcContact
+}
+
+type DestinationError struct {
+	Name    string
+	Age     uint8
+	Items   []DestItem
+	Contact struct {
+		Address struct {
+			Building uint8
+		}
+	}
+}
+
+func TestFieldError(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Src      SourceError
+		Path     string
+		SrcType  reflect.Type
+		DestType reflect.Type
+		Value    interface{}
+		Err      error
+	}{
+		{
+			Name:     "TopLevel",
+			Src:      SourceError{Age: "300"},
+			Path:     "Age",
+			SrcType:  reflect.TypeOf(""),
+			DestType: reflect.TypeOf(uint8(0)),
+			Value:    "300",
+			Err:      ErrOverflow,
+		},
+		{
+			Name:     "ListElement",
+			Src:      SourceError{Age: "1", Items: []SrcItem{{Price: "1"}, {Price: "x"}}},
+			Path:     "Items[1].Price",
+			SrcType:  reflect.TypeOf(""),
+			DestType: reflect.TypeOf(0.0),
+			Value:    "x",
+			Err:      strconv.ErrSyntax,
+		},
+		{
+			Name:     "Nested",
+			Src:      SourceError{Age: "1", Contact: SrcContact{Address: SrcAddress{Building: -1}}},
+			Path:     "Contact.Address.Building",
+			SrcType:  reflect.TypeOf(0),
+			DestType: reflect.TypeOf(uint8(0)),
+			Value:    -1,
+			Err:      ErrSignLoss,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			dest := DestinationError{}
+			err := CopyFrom(&dest, &tc.Src)
+
+			var fe *FieldError
+			if !errors.As(err, &fe) {
+				t.Fatalf("Expected a FieldError, but got: %v", err)
+			}
+			if fe.Path != tc.Path {
+				t.Errorf("Unexpected Path. Got: %v, Expected: %v", fe.Path, tc.Path)
+			}
+			if fe.SrcType != tc.SrcType || fe.DestType != tc.DestType {
+				t.Errorf("Unexpected types. Got: %v -> %v, Expected: %v -> %v", fe.SrcType, fe.DestType, tc.SrcType, tc.DestType)
+			}
+			if fe.Value != tc.Value {
+				t.Errorf("Unexpected Value. Got: %v, Expected: %v", fe.Value, tc.Value)
+			}
+			if !errors.Is(err, tc.Err) {
+				t.Errorf("Unexpected cause. Got: %v, Expected: %v", fe.Err, tc.Err)
+			}
+		})
+	}
+}
+
+func TestJoinPath(t *testing.T) {
+	testCases := []struct {
+		Parent   string
+		Child    string
+		Expected string
+	}{
+		{Parent: "", Child: "Name", Expected: "Name"},
+		{Parent: "Contact", Child: "Address", Expected: "Contact.Address"},
+		{Parent: "Items", Child: "[1]", Expected: "Items[1]"},
+		{Parent: "Items", Child: "[1].Price", Expected: "Items[1].Price"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Expected, func(t *testing.T) {
+			if result := joinPath(tc.Parent, tc.Child); result != tc.Expected {
+				t.Errorf("Unexpected result. Got: %v, Expected: %v", result, tc.Expected)
+			}
+		})
+	}
+}
+
+func TestCopyFromAll(t *testing.T) {
+	src := SourceError{
+		Name:    "taro",
+		Age:     "300",
+		Items:   []SrcItem{{Code: 1, Price: "x"}, {Code: 2, Price: "2"}, {Code: 3, Price: "y"}},
+		Contact: SrcContact{Address: SrcAddress{Building: -1}},
+	}
+	dest := DestinationError{}
+
+	err := CopyFromAll(&dest, &src)
+
+	var fes FieldErrors
+	if !errors.As(err, &fes) {
+		t.Fatalf("Expected FieldErrors, but got: %v", err)
+	}
+	paths := make([]string, len(fes))
+	for i, fe := range fes {
+		paths[i] = fe.Path
+	}
+	expectedPaths := []string{"Age", "Items[0].Price", "Items[2].Price", "Contact.Address.Building"}
+	if !reflect.DeepEqual(paths, expectedPaths) {
+		t.Errorf("Unexpected paths. Got: %v, Expected: %v", paths, expectedPaths)
+	}
+
+	for _, target := range []error{ErrOverflow, ErrSignLoss, strconv.ErrSyntax} {
+		if !errors.Is(err, target) {
+			t.Errorf("Expected errors.Is to match %v", target)
+		}
+	}
+	var fe *FieldError
+	if !errors.As(err, &fe) || fe.Path != "Age" {
+		t.Errorf("Expected errors.As to find the first FieldError, but got: %v", fe)
+	}
+
+	// 変換できた項目はコピーされる
+	if dest.Name != "taro" {
+		t.Errorf("Unexpected value for Name. Got: %v, Expected: %v", dest.Name, "taro")
+	}
+	expectedItems := []DestItem{{ID: "1"}, {ID: "2", Price: 2}, {ID: "3"}}
+	if !reflect.DeepEqual(dest.Items, expectedItems) {
+		t.Errorf("Unexpected value for Items. Got: %v, Expected: %v", dest.Items, expectedItems)
+	}
+}
+
+func TestCopyFromAllNoError(t *testing.T) {
+	dest := DestinationError{}
+	if err := CopyFromAll(&dest, &SourceError{Age: "1"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -3,164 +3,703 @@ package kop2cup
 import (
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	tFmt "github.com/enecom-kaisa/kop-to-cup/time_format"
 )
 
+/*
+コピー元の nil ポインタの扱いです。
+*/
+type NilPolicy int
+
+const (
+	NilZero  NilPolicy = iota // コピー先にゼロ値を設定
+	NilSkip                   // コピー先を変更しない
+	NilError                  // エラーを返す
+)
+
+/*
+タグ kopcup-nil の値を NilPolicy に変換します。
+*/
+func strToNilPolicy(str string) (NilPolicy, error) {
+	switch str {
+	case "", "zero":
+		return NilZero, nil
+	case "skip":
+		return NilSkip, nil
+	case "error":
+		return NilError, nil
+	}
+	return NilZero, errors.New("convert error: invalid kopcup-nil " + str)
+}
+
 /*
 構造体中の同一名称または同一タグの項目の内容をコピーします。
   - &dest コピー先ポインタ
-  - &src コピー元ポインタ
-  - tfmt タグで指定されていない場合のデフォルト日付フォーマット（省略時"2006-01-02T15:04:05+09:00")
+  - &src コピー元ポインタ（構造体の値も可）
+  - 項目の対応付けにはコピー元・コピー先の両方の kopcup-alias を使用（一方の宣言で双方向のコピーが可能。矛盾する宣言は ErrAliasConflict）
+  - kopcup-alias がない項目はタグ json、db の名前を別名として使用（WithTagKeys で変更可）
+  - kopcup-alias に "Address.City" のようなパスを指定するとネストした項目と対応付け（途中の構造体へのポインタは自動で確保）
+  - WithNameMatcher で大文字・小文字や snake_case / camelCase の違いを無視して項目名を対応付け（曖昧な場合は ErrAmbiguousName）
+  - opts 動作の指定（WithTimeFormat 等。tFmt.TimeFormat をそのまま指定した場合はデフォルト日付フォーマット（省略時"2006-01-02T15:04:05+09:00")）
+  - dest / src が構造体でない場合は ErrInvalidArgument を返す
   - intからboolへの変換仕様「true :0以外 / false :0」
+  - 整数・浮動小数点数は全ての型に対応し、桁あふれ・符号の喪失・小数部の切り捨てはエラー
+  - コピー元の埋め込み構造体の項目は昇格した項目として扱う（WithKeepEmbedded またはタグ kopcup-embed:"keep" で1項目として扱う）
+  - 型の異なるネストした構造体は項目単位で再帰的にコピー（kopcup-alias / kopcup-dateformat も各階層で有効）
+  - 要素型の異なるスライス・配列は要素単位で変換（配列 <-> スライスも可）
+  - ポインタは自動で参照・確保（*T <-> T）
+  - time.Time <-> 整数・浮動小数点数は Unix 時間として変換（単位はタグ kopcup-epoch "s" / "ms" / "us" / "ns" または WithEpochUnit、省略時は秒）
+  - time.Duration <-> 文字列（"90s", "1h30m", ISO 8601 "PT1H30M"）・整数・浮動小数点数に対応（数値の単位はタグ kopcup-duration "h" / "m" / "s" / "ms" / "us" / "ns" または WithDurationUnit、省略時は秒）
+  - kopcup-dateformat には和暦（tFmt.WarekiKanji "令和6年4月1日" / tFmt.WarekiLetter "R06.04.01"）も指定可
+  - kopcup-dateformat は "|" 区切りで複数指定可（文字列からの変換では順に試行、文字列への変換では先頭を使用）
+  - kopcup-dateformat / kopcup-tz / kopcup-epoch / kopcup-duration はコピー元・コピー先のいずれに指定しても可（両方の場合はコピー元を優先）
+  - 文字列から time.Time への変換のタイムゾーンはタグ kopcup-tz、WithLocation、DefaultLocation の順に適用
+  - nil ポインタの扱いはタグ kopcup-nil で指定（"zero": ゼロ値を設定（省略時） / "skip": コピーしない / "error": エラー）
+  - 変換に失敗した場合は項目のパスや型を持つ *FieldError を返す
+  - WithMerge の場合、コピー元で設定されている（ゼロ値・nil でない等）項目のみ上書き
+  - DefaultRegistry に登録された変換関数は組み込みの変換より優先
+  - 非公開の項目（コピー元・コピー先のいずれか）はコピーしない
+  - タグ kopcup:"-" の項目はコピーしない、kopcup:"omitempty" の項目はコピー元がゼロ値の場合にコピーしない（コピー元・コピー先のいずれに指定しても可）
+  - WithStrict の場合、コピー先のない項目は ErrUnmatched、非公開のためコピーしない項目は ErrUnexported、同じ深さの埋め込み構造体に同名があり曖昧な項目は ErrAmbiguousName
+*/
+func CopyFrom(dest interface{}, src interface{}, opts ...Option) error {
+	c, err := newConfig(opts...)
+	if err != nil {
+		return err
+	}
+	return copyFrom(dest, src, c)
+}
+
+/*
+日付フォーマットを指定して CopyFrom を呼び出します。
+  - tfmt デフォルト日付フォーマット（WithTimeFormats と同じ。文字列の定数も指定可）
+
+Deprecated: CopyFrom(dest, src, WithTimeFormats(tfmt...)) を使ってください。
+*/
+func CopyFromFormat(dest interface{}, src interface{}, tfmt ...tFmt.TimeFormat) error {
+	return CopyFrom(dest, src, WithTimeFormats(tfmt...))
+}
+
+/*
+CopyFrom と同様に項目の内容をコピーします。
+変換に失敗した項目があってもコピーを続け、失敗した全ての項目を FieldErrors として返します。
+*/
+func CopyFromAll(dest interface{}, src interface{}, opts ...Option) error {
+	c, err := newConfig(opts...)
+	if err != nil {
+		return err
+	}
+	c.errorMode = CollectAll
+	return copyFrom(dest, src, c)
+}
+
+/*
+src から新しい D を作成します。
+  - D は構造体、S は構造体または構造体へのポインタ（インターフェース型の場合は格納された値で判定）
+  - 変換に失敗した場合は、それまでにコピーした内容とエラーを返す
+*/
+func Copy[D, S any](src S, opts ...Option) (D, error) {
+	var dest D
+	err := CopyInto(&dest, src, opts...)
+	return dest, err
+}
+
+/*
+src の内容を既存の *D へコピーします。
+  - D は構造体、S は構造体または構造体へのポインタ（インターフェース型の場合は格納された値で判定）
+*/
+func CopyInto[D, S any](dest *D, src S, opts ...Option) error {
+	if t := reflect.TypeOf((*D)(nil)).Elem(); t.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: dest type %s is not a struct", ErrInvalidArgument, t)
+	}
+	// S がインターフェース型の場合は格納された値を copyFrom（srcStructValue）で判定
+	if t := reflect.TypeOf((*S)(nil)).Elem(); t.Kind() != reflect.Interface && t.Kind() != reflect.Struct && (t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct) {
+		return fmt.Errorf("%w: src type %s is not a struct", ErrInvalidArgument, t)
+	}
+	c, err := newConfig(opts...)
+	if err != nil {
+		return err
+	}
+	return copyFrom(dest, src, c)
+}
+
+func copyFrom(dest interface{}, src interface{}, c *config) error {
+	destValue, err := destStructValue(dest)
+	if err != nil {
+		return err
+	}
+	srcValue, err := srcStructValue(src)
+	if err != nil {
+		return err
+	}
+	return copyStruct(destValue, srcValue, c)
+}
+
+/*
+コピー先の構造体を返します。nil でない構造体へのポインタ以外はエラーです。
+*/
+func destStructValue(dest interface{}) (reflect.Value, error) {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("%w: dest must be a non-nil pointer to a struct, got %T", ErrInvalidArgument, dest)
+	}
+	return v.Elem(), nil
+}
+
+/*
+コピー元の構造体を返します。構造体または nil でない構造体へのポインタ以外はエラーです。
+*/
+func srcStructValue(src interface{}) (reflect.Value, error) {
+	v := reflect.ValueOf(src)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("%w: src must be a struct or a non-nil pointer to a struct, got %T", ErrInvalidArgument, src)
+	}
+	return v, nil
+}
+
+/*
+srcValue の各項目を destValue の同一名称または同一タグの項目へコピーします。
+ネストした構造体の項目にも同じ規則を再帰的に適用します。
+  - 項目の対応付けは型の組み合わせごとに planFor でキャッシュし、項目の定義順に逐次コピー
+  - FailFast の場合、最初に失敗した項目の *FieldError を返す
+  - CollectAll の場合、失敗した全ての項目を FieldErrors として返す
+  - strict の場合、コピー先のない項目を ErrUnmatched、非公開のためコピーしない項目を ErrUnexported として報告
 */
-func CopyFrom(dest interface{}, src interface{}, tfmt ...tFmt.TimeFormat) error {
-	destValue := reflect.ValueOf(dest).Elem()
-	srcValue := reflect.ValueOf(src).Elem()
-	defer func() error {
-		if r := recover(); r != nil {
-			return errors.New(fmt.Sprint("Recovered from panic: ", r))
+func copyStruct(destValue reflect.Value, srcValue reflect.Value, c *config) error {
+	p := planFor(destValue.Type(), srcValue.Type(), c.plan)
+	var all FieldErrors
+	if c.strict {
+		for _, f := range p.skipped {
+			srcField, _ := srcValue.FieldByIndexErr(f.srcIndex)
+			err := newFieldError(f.name, nil, srcField, f.err)
+			if c.errorMode != CollectAll {
+				return err
+			}
+			all = appendFieldErrors(all, err)
 		}
-		return nil
-	}()
-
-	var wg sync.WaitGroup
-	for i := 0; i < srcValue.NumField(); i++ {
-		wg.Add(1)
-		tf := []tFmt.TimeFormat{tFmt.RFC3339B}
-		if len(tfmt) != 0 {
-			tf[0] = tfmt[0]
-		}
-		srcField := srcValue.Field(i)
-		if formatter := srcValue.Type().Field(i).Tag.Get("kopcup-dateformat"); formatter != "" {
-			if t, err := tFmt.StrToTimeFormat(formatter); err != nil {
+	}
+	for i := range p.fields {
+		if err := c.copyField(destValue, srcValue, &p.fields[i]); err != nil {
+			if c.errorMode != CollectAll {
 				return err
-			} else {
-				tf[0] = t
 			}
+			all = appendFieldErrors(all, err)
+		}
+	}
+	if len(all) != 0 {
+		return all
+	}
+	return nil
+}
+
+/*
+fieldPlan に従って1項目をコピーします。
+*/
+func (c *config) copyField(destValue reflect.Value, srcValue reflect.Value, f *fieldPlan) error {
+	srcField, err := srcValue.FieldByIndexErr(f.srcIndex)
+	if err != nil {
+		// nil の埋め込みポインタを経由する項目はコピーしない
+		return nil
+	}
+	if f.err != nil {
+		return newFieldError(f.name, f.destType, srcField, f.err)
+	}
+	if f.omitEmpty && srcField.IsZero() || !c.isSet(srcField) {
+		return nil
+	}
+
+	policy := c.nilPolicy
+	if f.nilPolicy != nil {
+		policy = *f.nilPolicy
+	}
+	if srcField.Kind() == reflect.Ptr && srcField.IsNil() {
+		switch policy {
+		case NilSkip:
+			return nil
+		case NilError:
+			return newFieldError(f.name, f.destType, srcField, ErrNilPointer)
+		}
+	}
+
+	destField := destFieldByIndex(destValue, f.destIndex)
+	if !destField.IsValid() || !destField.CanSet() || !srcField.CanInterface() {
+		if c.strict {
+			return newFieldError(f.name, f.destType, srcField, ErrUnexported)
 		}
+		return nil
+	}
+
+	fc, err := c.override(f.tags)
+	if err != nil {
+		return newFieldError(f.name, f.destType, srcField, err)
+	}
+	v, err := convertDestToSrcType(destField, srcField, fc)
+	if v.IsValid() {
+		destField.Set(v)
+	}
+	if err != nil {
+		return newFieldError(f.name, f.destType, srcField, err)
+	}
+	return nil
+}
 
-		go func(i int) {
-			defer wg.Done()
-			if destField := destValue.FieldByName(srcValue.Type().Field(i).Tag.Get("kopcup-alias")); destField.IsValid() {
-				destField.Set(convertDestToSrcType(destField, srcField, tf[0]).Convert(destField.Type()))
-			} else if destField := destValue.FieldByName(srcValue.Type().Field(i).Name); destField.IsValid() {
-				destField.Set(convertDestToSrcType(destField, srcField, tf[0]).Convert(destField.Type()))
+/*
+index の項目を返します。経由する nil のポインタ（埋め込み構造体・パスの途中の構造体）は領域を確保します。
+確保できない（非公開の埋め込みポインタ）場合は無効な値を返します。
+*/
+func destFieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
 			}
-		}(i)
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+/*
+ErrorMode に従って項目ごとのエラーをまとめます。
+*/
+func (c *config) result(errs []error) error {
+	var all FieldErrors
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if c.errorMode != CollectAll {
+			return err
+		}
+		all = appendFieldErrors(all, err)
+	}
+	if len(all) != 0 {
+		return all
 	}
-	wg.Wait()
 	return nil
 }
 
-func convertDestToSrcType(destField reflect.Value, srcField reflect.Value, tfmt ...tFmt.TimeFormat) reflect.Value {
-	if destField.Type().Kind() != srcField.Type().Kind() {
-		switch destField.Type().Kind() {
-		case reflect.TypeOf("").Kind():
-			return reflect.ValueOf(convertToString(srcField))
-		case reflect.TypeOf(1).Kind():
-			return reflect.ValueOf(convertToInt(srcField))
-		case reflect.TypeOf(3.14).Kind():
-			return reflect.ValueOf(convertToFloat(srcField))
-		case reflect.TypeOf(true).Kind():
-			return reflect.ValueOf(convertToBool(srcField))
-		case reflect.TypeOf(time.Time{}).Kind():
-			return reflect.ValueOf(convertToTime(srcField, tfmt...))
+/*
+コピー元の値をコピー先項目の型へ変換します。
+*/
+func convertDestToSrcType(destField reflect.Value, srcField reflect.Value, c *config) (reflect.Value, error) {
+	destType := destField.Type()
+	if v, ok, err := c.convertByRegistry(destType, srcField); ok {
+		return v, err
+	}
+	if destType != srcField.Type() {
+		if srcField.Kind() == reflect.Ptr {
+			if srcField.IsNil() {
+				return reflect.Zero(destType), nil
+			}
+			return convertDestToSrcType(destField, srcField.Elem(), c)
+		}
+		if destField.Kind() == reflect.Ptr {
+			return convertToPointer(destField, srcField, c)
+		}
+	}
+	if isDurationConversion(destType, srcField.Type()) {
+		return c.convertDuration(destField, srcField)
+	}
+	if isNestedStruct(destType) && isNestedStruct(srcField.Type()) && (destType != srcField.Type() || c.merge != MergeNone) {
+		return convertToStruct(destField, srcField, c)
+	}
+	if isList(destType) && isList(srcField.Type()) && destType != srcField.Type() {
+		return convertToList(destField, srcField, c)
+	}
+
+	var v interface{}
+	var err error
+	if destType.Kind() != srcField.Type().Kind() {
+		if srcField.Type() == reflect.TypeOf(time.Time{}) {
+			srcField = c.timeToEpochValue(srcField, destType)
+		}
+		switch destType.Kind() {
+		case reflect.String:
+			v, err = convertToString(srcField, c.timeFormats()...)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			var i int64
+			if i, err = convertToInt(srcField); err == nil && destField.OverflowInt(i) {
+				err = fmt.Errorf("%w: %d to %s", ErrOverflow, i, destType)
+			}
+			v = i
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			var u uint64
+			if u, err = convertToUint(srcField); err == nil && destField.OverflowUint(u) {
+				err = fmt.Errorf("%w: %d to %s", ErrOverflow, u, destType)
+			}
+			v = u
+		case reflect.Float32, reflect.Float64:
+			var f float64
+			if f, err = convertToFloat(srcField); err == nil && destField.OverflowFloat(f) {
+				err = fmt.Errorf("%w: %g to %s", ErrOverflow, f, destType)
+			}
+			v = f
+		case reflect.Bool:
+			v, err = convertToBool(srcField)
+		case reflect.Struct:
+			// time.Time（または time.Time から変換できる型）以外の構造体には変換しない
+			if !reflect.TypeOf(time.Time{}).ConvertibleTo(destType) {
+				return reflect.Value{}, ErrUnsupported
+			}
+			v, err = convertToTime(srcField, c.loc, c.epoch, c.timeFormats()...)
+		default:
+			// interface{} 等、変換関数のない型は reflect の変換規則に従う
+			if !srcField.Type().ConvertibleTo(destType) {
+				return reflect.Value{}, ErrUnsupported
+			}
+			return srcField.Convert(destType), nil
+		}
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(v).Convert(destType), nil
+	}
+	if srcField.Type() == destType {
+		return srcField, nil
+	}
+	if !srcField.Type().ConvertibleTo(destType) {
+		return reflect.Value{}, ErrUnsupported
+	}
+	return srcField.Convert(destType), nil
+}
+
+/*
+コピー先が数値型、または Unix 時間の単位が指定されている場合の文字列型であれば、
+time.Time を Unix 時間の数値に変換します。それ以外は srcField をそのまま返します。
+*/
+func (c *config) timeToEpochValue(srcField reflect.Value, destType reflect.Type) reflect.Value {
+	t := srcField.Interface().(time.Time)
+	switch destType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return reflect.ValueOf(timeToEpoch(t, c.epochUnit()))
+	case reflect.Float32, reflect.Float64:
+		return reflect.ValueOf(timeToEpochFloat(t, c.epochUnit()))
+	case reflect.String:
+		if c.epoch > 0 {
+			return reflect.ValueOf(timeToEpoch(t, c.epoch))
 		}
 	}
 	return srcField
+}
+
+/*
+time.Duration と文字列・整数・浮動小数点数を相互に変換します。
+*/
+func (c *config) convertDuration(destField reflect.Value, srcField reflect.Value) (reflect.Value, error) {
+	destType := destField.Type()
+	if destType == reflect.TypeOf(time.Duration(0)) {
+		d, err := convertToDuration(srcField, c.durationUnit())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(d), nil
+	}
+
+	v, err := convertFromDuration(time.Duration(srcField.Int()), destType, c.durationUnit())
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return convertDestToSrcType(destField, v, c)
+}
+
+/*
+time.Time 以外の構造体型であれば true を返します。
+*/
+func isNestedStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != reflect.TypeOf(time.Time{})
+}
+
+/*
+コピー先のポインタ型へ変換します。
+コピー先の指す値は書き換えず、新しく確保した領域に変換結果を格納します。
+*/
+func convertToPointer(destField reflect.Value, srcField reflect.Value, c *config) (reflect.Value, error) {
+	ptr := reflect.New(destField.Type().Elem())
+	if !destField.IsNil() {
+		ptr.Elem().Set(destField.Elem())
+	}
+	v, err := convertDestToSrcType(ptr.Elem(), srcField, c)
+	if !v.IsValid() {
+		return reflect.Value{}, err
+	}
+	ptr.Elem().Set(v)
+	return ptr, err
+}
 
+/*
+スライスまたは配列型であれば true を返します。
+*/
+func isList(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice || t.Kind() == reflect.Array
 }
 
-func convertToString(srcField reflect.Value, tfmt ...tFmt.TimeFormat) string {
+/*
+要素型の異なるスライス・配列同士を要素単位で変換します。
+  - 要素の変換には項目と同じ規則を適用
+  - nil スライスは nil スライスへ変換
+  - コピー先が配列の場合、要素数が足りなければエラー、余った要素はゼロ値
+  - CollectAll の場合は失敗した要素をゼロ値のまま残し、変換結果とエラーの両方を返す
+*/
+func convertToList(destField reflect.Value, srcField reflect.Value, c *config) (reflect.Value, error) {
+	destType := destField.Type()
+	var list reflect.Value
+	if destType.Kind() == reflect.Slice {
+		if srcField.Kind() == reflect.Slice && srcField.IsNil() {
+			return reflect.Zero(destType), nil
+		}
+		list = reflect.MakeSlice(destType, srcField.Len(), srcField.Len())
+	} else {
+		if srcField.Len() > destType.Len() {
+			return reflect.Value{}, fmt.Errorf("%w: %d elements to %s", ErrOverflow, srcField.Len(), destType)
+		}
+		list = reflect.New(destType).Elem()
+	}
+	errs := make([]error, srcField.Len())
+	for i := 0; i < srcField.Len(); i++ {
+		elem := list.Index(i)
+		v, err := convertDestToSrcType(elem, srcField.Index(i), c)
+		if err != nil {
+			errs[i] = newFieldError(fmt.Sprintf("[%d]", i), elem.Type(), srcField.Index(i), err)
+			if c.errorMode != CollectAll {
+				break
+			}
+		}
+		if v.IsValid() {
+			elem.Set(v)
+		}
+	}
+	if err := c.result(errs); err != nil {
+		if c.errorMode != CollectAll {
+			return reflect.Value{}, err
+		}
+		return list, err
+	}
+	return list, nil
+}
+
+/*
+型の異なる構造体同士を項目単位でコピーします。
+コピー先の既存の値を引き継ぎ、対応する項目のみ上書きします。
+CollectAll の場合は変換できた項目のみ上書きし、変換結果とエラーの両方を返します。
+*/
+func convertToStruct(destField reflect.Value, srcField reflect.Value, c *config) (reflect.Value, error) {
+	nested := reflect.New(destField.Type()).Elem()
+	nested.Set(destField)
+	if err := copyStruct(nested, srcField, c); err != nil {
+		if c.errorMode != CollectAll {
+			return reflect.Value{}, err
+		}
+		return nested, err
+	}
+	return nested, nil
+}
+
+/*
+文字列に変換します。time.Time は tfmt の先頭の日付フォーマットで書式化します。
+*/
+func convertToString(srcField reflect.Value, tfmt ...tFmt.TimeFormat) (string, error) {
 	switch srcField.Type().Kind() {
-	case reflect.TypeOf(int(1)).Kind():
-		return strconv.Itoa(srcField.Interface().(int))
-	case reflect.TypeOf(time.Time{}).Kind():
-		return srcField.Interface().(time.Time).Format(tfmt[0].String())
-	case reflect.TypeOf(true).Kind():
-		return strconv.FormatBool(srcField.Interface().(bool))
-	default:
-		panic(errors.New("convert error: cannot convert to string type"))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(srcField.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(srcField.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(srcField.Float(), 'f', -1, srcField.Type().Bits()), nil
+	case reflect.Struct:
+		if srcField.Type() == reflect.TypeOf(time.Time{}) && len(tfmt) != 0 {
+			return tfmt[0].Format(srcField.Interface().(time.Time))
+		}
+	case reflect.Bool:
+		return strconv.FormatBool(srcField.Bool()), nil
 	}
+	return "", ErrUnsupported
 }
 
-func convertToInt(srcField reflect.Value) int {
+/*
+int64 に変換します。int64 の範囲外や小数部を持つ浮動小数点数はエラーです。
+*/
+func convertToInt(srcField reflect.Value) (int64, error) {
 	switch srcField.Type().Kind() {
-	case reflect.TypeOf("").Kind():
-		if val, err := strconv.Atoi(srcField.Interface().(string)); err != nil {
-			panic(err)
-		} else {
-			return val
+	case reflect.String:
+		val, err := strconv.ParseInt(srcField.String(), 10, 64)
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, fmt.Errorf("%w: %s to int64", ErrOverflow, srcField.String())
+		}
+		return val, err
+	case reflect.Bool:
+		if srcField.Bool() {
+			return 1, nil
 		}
-	case reflect.TypeOf(true).Kind():
-		if srcField.Interface().(bool) {
-			return 1
+		return 0, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return srcField.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if srcField.Uint() > math.MaxInt64 {
+			return 0, fmt.Errorf("%w: %d to int64", ErrOverflow, srcField.Uint())
 		}
-		return 0
+		return int64(srcField.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := srcField.Float()
+		if math.IsNaN(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return 0, fmt.Errorf("%w: %g to int64", ErrOverflow, f)
+		}
+		if f != math.Trunc(f) {
+			return 0, fmt.Errorf("%w: %g to int64", ErrTruncated, f)
+		}
+		return int64(f), nil
 	default:
-		panic(errors.New("convert error: cannot convert to int type"))
+		return 0, ErrUnsupported
 	}
+}
 
+/*
+uint64 に変換します。負の値や小数部を持つ浮動小数点数はエラーです。
+*/
+func convertToUint(srcField reflect.Value) (uint64, error) {
+	switch srcField.Type().Kind() {
+	case reflect.String:
+		val, err := strconv.ParseUint(srcField.String(), 10, 64)
+		if err != nil {
+			if errors.Is(err, strconv.ErrRange) {
+				return 0, fmt.Errorf("%w: %s to uint64", ErrOverflow, srcField.String())
+			}
+			if i, ierr := strconv.ParseInt(srcField.String(), 10, 64); ierr == nil && i < 0 {
+				return 0, fmt.Errorf("%w: %d to uint64", ErrSignLoss, i)
+			}
+			return 0, err
+		}
+		return val, nil
+	case reflect.Bool:
+		if srcField.Bool() {
+			return 1, nil
+		}
+		return 0, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if srcField.Int() < 0 {
+			return 0, fmt.Errorf("%w: %d to uint64", ErrSignLoss, srcField.Int())
+		}
+		return uint64(srcField.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return srcField.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		f := srcField.Float()
+		if f < 0 {
+			return 0, fmt.Errorf("%w: %g to uint64", ErrSignLoss, f)
+		}
+		if math.IsNaN(f) || f >= math.MaxUint64 {
+			return 0, fmt.Errorf("%w: %g to uint64", ErrOverflow, f)
+		}
+		if f != math.Trunc(f) {
+			return 0, fmt.Errorf("%w: %g to uint64", ErrTruncated, f)
+		}
+		return uint64(f), nil
+	default:
+		return 0, ErrUnsupported
+	}
 }
 
-func convertToTime(srcField reflect.Value, tfmt ...tFmt.TimeFormat) time.Time {
+/*
+time.Time に変換します。
+  - 数値は epoch 単位（0 の場合は秒）の Unix 時間として解釈
+  - 文字列は loc（nil の場合は DefaultLocation）の時刻として解釈
+  - epoch が指定されている場合、数字のみの文字列は Unix 時間として解釈
+  - tfmt を順に試し、いずれでも解析できなければ試した全ての日付フォーマットをエラーに含める
+*/
+func convertToTime(srcField reflect.Value, loc *time.Location, epoch time.Duration, tfmt ...tFmt.TimeFormat) (time.Time, error) {
+	unit := epoch
+	if unit <= 0 {
+		unit = time.Second
+	}
 	switch srcField.Type().Kind() {
-	case reflect.TypeOf(int(1)).Kind():
-		return time.Unix(int64(srcField.Interface().(int)), 0)
-	case reflect.TypeOf("").Kind():
-		jst, _ := time.LoadLocation("Asia/Tokyo")
-		if t, err := time.ParseInLocation(tfmt[0].String(), srcField.Interface().(string), jst); err != nil {
-			panic(errors.New("convert error: time perse err"))
-		} else {
-			return t
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return epochToTime(srcField.Int(), unit), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v, err := convertToInt(srcField)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return epochToTime(v, unit), nil
+	case reflect.Float32, reflect.Float64:
+		return epochFloatToTime(srcField.Float(), unit)
+	case reflect.String:
+		if epoch > 0 {
+			if t, ok, err := parseEpoch(srcField.String(), unit); ok {
+				return t, err
+			}
 		}
+		if len(tfmt) == 0 {
+			return time.Time{}, errors.New("convert error: time format not specified")
+		}
+		if loc == nil {
+			loc = DefaultLocation
+		}
+		errs := make([]error, len(tfmt))
+		tried := make([]string, len(tfmt))
+		for i := range tfmt {
+			t, err := tfmt[i].Parse(srcField.String(), loc)
+			if err == nil {
+				return t, nil
+			}
+			errs[i] = err
+			tried[i] = strconv.Quote(tfmt[i].String())
+		}
+		return time.Time{}, fmt.Errorf("convert error: time parse error (tried %s): %w", strings.Join(tried, ", "), errors.Join(errs...))
 	default:
-		panic(errors.New("convert error: cannot convert to time type"))
+		return time.Time{}, ErrUnsupported
 	}
 }
 
-func convertToFloat(srcField reflect.Value) float64 {
+func convertToFloat(srcField reflect.Value) (float64, error) {
 	switch srcField.Type().Kind() {
-	case reflect.TypeOf(int(1)).Kind():
-		return float64(srcField.Interface().(int))
-	case reflect.TypeOf("").Kind():
-		if f, err := strconv.ParseFloat(srcField.Interface().(string), 64); err != nil {
-			panic(errors.New("convert error"))
-		} else {
-			return f
-		}
-	case reflect.TypeOf(true).Kind():
-		if srcField.Interface().(bool) {
-			return 1.0
-		}
-		return 0.0
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(srcField.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(srcField.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return srcField.Float(), nil
+	case reflect.String:
+		return strconv.ParseFloat(srcField.String(), 64)
+	case reflect.Bool:
+		if srcField.Bool() {
+			return 1.0, nil
+		}
+		return 0.0, nil
 	default:
-		panic(errors.New("convert error: cannot convert to float64 type"))
+		return 0, ErrUnsupported
 	}
 }
 
-func convertToBool(srcField reflect.Value) bool {
+func convertToBool(srcField reflect.Value) (bool, error) {
 	switch srcField.Type().Kind() {
-	case reflect.TypeOf(int(1)).Kind():
-		if srcField.Interface().(int) != 0 {
-			return true
-		}
-		return false
-	case reflect.TypeOf("").Kind():
-		if strings.EqualFold(srcField.Interface().(string), "true") {
-			return true
-		} else if strings.EqualFold(srcField.Interface().(string), "false") {
-			return false
-		} else {
-			panic(errors.New("convert error: cannot convert to bool type"))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return srcField.Int() != 0, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return srcField.Uint() != 0, nil
+	case reflect.Float32, reflect.Float64:
+		return srcField.Float() != 0, nil
+	case reflect.String:
+		if strings.EqualFold(srcField.String(), "true") {
+			return true, nil
+		} else if strings.EqualFold(srcField.String(), "false") {
+			return false, nil
 		}
+		return false, fmt.Errorf("%w: %q to bool", ErrUnsupported, srcField.String())
 	default:
-		panic(errors.New("convert error: cannot convert to bool type"))
+		return false, ErrUnsupported
 	}
 }
@@ -1,8 +1,11 @@
 package kop2cup
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -19,13 +22,15 @@ func TestConvertToTime(t *testing.T) {
 	// テストデータ
 	intField := reflect.ValueOf(1637277000)                          // Unix timestamp: 2021-11-19 12:30:00
 	stringField := reflect.ValueOf("2021-11-19T12:30:00+09:00")      // RFC3339 formatted string
-	invalidTypeField := reflect.ValueOf(3.14)                        // Invalid type (float64)
+	floatField := reflect.ValueOf(1637277000.5)                      // Unix timestamp with fraction
+	invalidTypeField := reflect.ValueOf(true)                        // Invalid type (bool)
 	invalidValueField := reflect.ValueOf("invalidDateString")        // Invalid date string
 	missingTimeFormatField := reflect.ValueOf("2021-11-19T12:30:00") // Missing time format
 
 	testCases := []TestData{
 		{SrcField: intField, Expected: time.Unix(1637277000, 0)},
 		{SrcField: stringField, Expected: time.Date(2021, 11, 19, 12, 30, 0, 0, time.FixedZone("JST", 9*60*60))},
+		{SrcField: floatField, Expected: time.Unix(1637277000, 500000000)},
 		{SrcField: invalidTypeField, ShouldFail: true},
 		{SrcField: invalidValueField, ShouldFail: true},
 		{SrcField: missingTimeFormatField, ShouldFail: true},
@@ -33,18 +38,17 @@ func TestConvertToTime(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run("", func(t *testing.T) {
-			defer func() {
-				if r := recover(); r != nil {
-					if !tc.ShouldFail {
-						t.Errorf("Unexpected panic: %v", r)
-					}
-				}
-			}()
-
-			result := convertToTime(tc.SrcField, tFmt.RFC3339)
+			result, err := convertToTime(tc.SrcField, nil, 0, tFmt.RFC3339)
 
 			if tc.ShouldFail {
-				t.Error("Expected an error, but got none.")
+				if err == nil {
+					t.Error("Expected an error, but got none.")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
 				return
 			}
 
@@ -58,31 +62,37 @@ func TestConvertToTime(t *testing.T) {
 func TestConvertToInt(t *testing.T) {
 	testCases := []struct {
 		Input      reflect.Value
-		Expected   int
+		Expected   int64
 		ShouldFail bool
 	}{
 		{Input: reflect.ValueOf("42"), Expected: 42},
 		{Input: reflect.ValueOf(true), Expected: 1},
 		{Input: reflect.ValueOf(false), Expected: 0},
-		{Input: reflect.ValueOf(42), ShouldFail: true},        // Invalid type
-		{Input: reflect.ValueOf(3.14), ShouldFail: true},      // Invalid type
-		{Input: reflect.ValueOf("invalid"), ShouldFail: true}, // Invalid value
+		{Input: reflect.ValueOf(42), Expected: 42},
+		{Input: reflect.ValueOf(int8(-8)), Expected: -8},
+		{Input: reflect.ValueOf(uint32(32)), Expected: 32},
+		{Input: reflect.ValueOf(float32(2)), Expected: 2},
+		{Input: reflect.ValueOf(uint64(math.MaxUint64)), ShouldFail: true}, // Overflow
+		{Input: reflect.ValueOf("9223372036854775808"), ShouldFail: true},  // Overflow
+		{Input: reflect.ValueOf(1e20), ShouldFail: true},                   // Overflow
+		{Input: reflect.ValueOf(3.14), ShouldFail: true},                   // Truncated
+		{Input: reflect.ValueOf("invalid"), ShouldFail: true},              // Invalid value
+		{Input: reflect.ValueOf(time.Time{}), ShouldFail: true},            // Invalid type
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.Input.Type().String(), func(t *testing.T) {
-			defer func() {
-				if r := recover(); r != nil {
-					if !tc.ShouldFail {
-						t.Errorf("Unexpected panic: %v", r)
-					}
-				}
-			}()
-
-			result := convertToInt(tc.Input)
+			result, err := convertToInt(tc.Input)
 
 			if tc.ShouldFail {
-				t.Error("Expected an error, but got none.")
+				if err == nil {
+					t.Error("Expected an error, but got none.")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
 				return
 			}
 
@@ -95,36 +105,37 @@ func TestConvertToInt(t *testing.T) {
 
 func TestConvertToString(t *testing.T) {
 	testCases := []struct {
-		Input       reflect.Value
-		Expected    string
-		TimeFormat  tFmt.TimeFormat
-		ShouldFail  bool
-		ShouldPanic bool
+		Input      reflect.Value
+		Expected   string
+		TimeFormat tFmt.TimeFormat
+		ShouldFail bool
 	}{
 		{Input: reflect.ValueOf(42), Expected: "42"},
 		{Input: reflect.ValueOf(true), Expected: "true"},
 		{Input: reflect.ValueOf(false), Expected: "false"},
 		{Input: reflect.ValueOf(time.Date(2021, 11, 19, 12, 30, 0, 0, time.UTC)), Expected: "2021-11-19T12:30:00Z", TimeFormat: tFmt.RFC3339},
-		{Input: reflect.ValueOf("test"), ShouldPanic: true},                         // Invalid type
-		{Input: reflect.ValueOf(3.14), ShouldPanic: true},                           // Invalid type
-		{Input: reflect.ValueOf(time.Now()), ShouldPanic: true},                     // Missing time format
-		{Input: reflect.ValueOf(struct{ Field int }{Field: 42}), ShouldPanic: true}, // Invalid type
+		{Input: reflect.ValueOf(uint8(8)), Expected: "8"},
+		{Input: reflect.ValueOf(int64(-64)), Expected: "-64"},
+		{Input: reflect.ValueOf(3.14), Expected: "3.14"},
+		{Input: reflect.ValueOf(float32(0.1)), Expected: "0.1"},
+		{Input: reflect.ValueOf("test"), ShouldFail: true},                         // Invalid type
+		{Input: reflect.ValueOf(time.Now())},                                       // Missing time format
+		{Input: reflect.ValueOf(struct{ Field int }{Field: 42}), ShouldFail: true}, // Invalid type
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.Input.Type().String(), func(t *testing.T) {
-			defer func() {
-				if r := recover(); r != nil {
-					if !tc.ShouldPanic {
-						t.Errorf("Unexpected panic: %v", r)
-					}
-				}
-			}()
-
-			result := convertToString(tc.Input, tc.TimeFormat)
+			result, err := convertToString(tc.Input, tc.TimeFormat)
 
 			if tc.ShouldFail {
-				t.Error("Expected an error, but got none.")
+				if err == nil {
+					t.Error("Expected an error, but got none.")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
 				return
 			}
 
@@ -139,39 +150,49 @@ func TestConvertDestToSrcType(t *testing.T) {
 	now := time.Now()
 
 	testCases := []struct {
-		DestType    reflect.Type
-		SrcType     reflect.Type
-		DestValue   interface{}
-		SrcValue    interface{}
-		TimeFormat  tFmt.TimeFormat
-		Expected    interface{}
-		ShouldPanic bool
+		DestType   reflect.Type
+		SrcType    reflect.Type
+		DestValue  interface{}
+		SrcValue   interface{}
+		TimeFormat tFmt.TimeFormat
+		Expected   interface{}
+		ShouldFail bool
 	}{
 		{DestType: reflect.TypeOf(""), SrcType: reflect.TypeOf("test"), DestValue: "", SrcValue: "test", Expected: "test"},
 		{DestType: reflect.TypeOf(1), SrcType: reflect.TypeOf(42), DestValue: 0, SrcValue: 42, Expected: 42},
 		{DestType: reflect.TypeOf(time.Time{}), SrcType: reflect.TypeOf(now), DestValue: time.Time{}, SrcValue: now, Expected: now},
-		{DestType: reflect.TypeOf(1), SrcType: reflect.TypeOf("test"), DestValue: 0, SrcValue: "test", Expected: "test", ShouldPanic: true}, // Mismatched types, should return srcField
+		{DestType: reflect.TypeOf(1), SrcType: reflect.TypeOf("test"), DestValue: 0, SrcValue: "test", ShouldFail: true}, // Mismatched types, invalid value
+		{DestType: reflect.TypeOf((*interface{})(nil)).Elem(), SrcType: reflect.TypeOf("test"), SrcValue: "test", Expected: "test"},
+		{DestType: reflect.TypeOf((*interface{})(nil)).Elem(), SrcType: reflect.TypeOf(42), SrcValue: 42, Expected: 42},
+		{DestType: reflect.TypeOf((*fmt.Stringer)(nil)).Elem(), SrcType: reflect.TypeOf(42), SrcValue: 42, ShouldFail: true},
+		{DestType: reflect.TypeOf([]int{}), SrcType: reflect.TypeOf(42), DestValue: []int(nil), SrcValue: 42, ShouldFail: true},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.DestType.String(), func(t *testing.T) {
-			defer func() {
-				if r := recover(); r != nil {
-					if !tc.ShouldPanic {
-						t.Errorf("Unexpected panic: %v", r)
-					}
-				}
-			}()
-
 			destField := reflect.New(tc.DestType).Elem()
-			destField.Set(reflect.ValueOf(tc.DestValue))
+			if tc.DestValue != nil {
+				destField.Set(reflect.ValueOf(tc.DestValue))
+			}
 
 			srcField := reflect.New(tc.SrcType).Elem()
 			srcField.Set(reflect.ValueOf(tc.SrcValue))
 
-			result := convertDestToSrcType(destField, srcField, tc.TimeFormat).Interface()
+			result, err := convertDestToSrcType(destField, srcField, &config{tfmts: []tFmt.TimeFormat{tc.TimeFormat}})
+
+			if tc.ShouldFail {
+				if err == nil {
+					t.Error("Expected an error, but got none.")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
 
-			if !reflect.DeepEqual(result, tc.Expected) {
+			if result := result.Interface(); !reflect.DeepEqual(result, tc.Expected) {
 				t.Errorf("Unexpected result. Got: %v, Expected: %v", result, tc.Expected)
 			}
 		})
@@ -199,7 +220,7 @@ type SourceStruct struct {
 	TimeField5   time.Time `kopcup-alias:"TimeField6" kopcup-dateformat:"2006-01-02T15:04:05Z07:00"`
 	StringField6 string
 	IntField6    int
-	TimeField6   time.Time
+	TimeField6   time.Time `kopcup:"-"` // TimeField5 の kopcup-alias と重複するためコピーしない
 }
 
 type DestinationStruct struct {
@@ -345,29 +366,26 @@ func TestConvertToFloat(t *testing.T) {
 		name     string
 		input    interface{}
 		expected float64
-		panics   bool // このケースでパニックが期待されているか
+		fails    bool // このケースでエラーが期待されているか
 	}{
 		{"ConvertInt", 42, 42.0, false},
 		{"ConvertString", "3.14", 3.14, false},
 		{"ConvertBoolTrue", true, 1.0, false},
 		{"ConvertBoolFalse", false, 0.0, false},
-		{"ConvertInvalidType", "invalid type", 0.0, true}, // このケースではパニックが期待されます
+		{"ConvertUint16", uint16(16), 16.0, false},
+		{"ConvertFloat32", float32(0.5), 0.5, false},
+		{"ConvertInvalidType", "invalid type", 0.0, true}, // このケースではエラーが期待されます
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			defer func() {
-				if test.panics {
-					if r := recover(); r == nil {
-						t.Errorf("Expected a panic, but didn't get one")
-					}
-				}
-			}()
-
-			result := convertToFloat(reflect.ValueOf(test.input))
+			result, err := convertToFloat(reflect.ValueOf(test.input))
+			if test.fails != (err != nil) {
+				t.Errorf("Unexpected error: %v", err)
+			}
 
 			// 期待される結果と実際の結果を比較
-			if result != test.expected && !test.panics {
+			if result != test.expected && !test.fails {
 				t.Errorf("Expected: %f, Got: %f", test.expected, result)
 			}
 		})
@@ -379,33 +397,731 @@ func TestConvertToBool(t *testing.T) {
 		name     string
 		input    interface{}
 		expected bool
-		panics   bool // このケースでパニックが期待されているか
+		fails    bool // このケースでエラーが期待されているか
 	}{
 		{"ConvertIntTrue", 100, true, false},
 		{"ConvertIntFalse", 0, false, false},
+		{"ConvertUint", uint(1), true, false},
+		{"ConvertFloat", 0.0, false, false},
 		{"ConvertString", "true", true, false},
 		{"ConvertString", "True", true, false},
 		{"ConvertString", "false", false, false},
 		{"ConvertString", "False", false, false},
-		{"ConvertInvalidType", "invalid type", true, true}, // このケースではパニックが期待されます
+		{"ConvertInvalidType", "invalid type", true, true}, // このケースではエラーが期待されます
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			defer func() {
-				if test.panics {
-					if r := recover(); r == nil {
-						t.Errorf("Expected a panic, but didn't get one")
-					}
-				}
-			}()
-
-			result := convertToBool(reflect.ValueOf(test.input))
+			result, err := convertToBool(reflect.ValueOf(test.input))
+			if test.fails != (err != nil) {
+				t.Errorf("Unexpected error: %v", err)
+			}
 
 			// 期待される結果と実際の結果を比較
-			if result != test.expected && !test.panics {
+			if result != test.expected && !test.fails {
 				t.Errorf("Expected: %v, Got: %v", test.expected, result)
 			}
 		})
 	}
 }
+
+type SrcAddress struct {
+	Zip      string `kopcup-alias:"PostalCode"`
+	City     string
+	Building int
+	MovedAt  time.Time `kopcup-dateformat:"2006/01/02"`
+}
+
+type SrcContact struct {
+	Phone   string
+	Address SrcAddress
+}
+
+type SourceNested struct {
+	Name    string
+	Contact SrcContact
+	Home    SrcAddress `kopcup-alias:"Residence"`
+}
+
+type DestAddress struct {
+	PostalCode string
+	City       string
+	Building   string
+	MovedAt    string
+	Note       string
+}
+
+type DestContact struct {
+	Phone   string
+	Address DestAddress
+}
+
+type DestinationNested struct {
+	Name      string
+	Contact   DestContact
+	Residence DestAddress
+}
+
+func TestCopyFromNested(t *testing.T) {
+	moved := time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)
+	src := SourceNested{
+		Name: "taro",
+		Contact: SrcContact{
+			Phone:   "03-0000-0000",
+			Address: SrcAddress{Zip: "100-0001", City: "Chiyoda", Building: 12, MovedAt: moved},
+		},
+		Home: SrcAddress{Zip: "530-0001", City: "Kita", Building: 3, MovedAt: moved},
+	}
+	dest := DestinationNested{Residence: DestAddress{Note: "keep"}}
+
+	if err := CopyFrom(&dest, &src); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+
+	expected := DestinationNested{
+		Name: "taro",
+		Contact: DestContact{
+			Phone:   "03-0000-0000",
+			Address: DestAddress{PostalCode: "100-0001", City: "Chiyoda", Building: "12", MovedAt: "2023/04/01"},
+		},
+		Residence: DestAddress{PostalCode: "530-0001", City: "Kita", Building: "3", MovedAt: "2023/04/01", Note: "keep"},
+	}
+	if !reflect.DeepEqual(dest, expected) {
+		t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", dest, expected)
+	}
+}
+
+func TestConvertToList(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		DestType   reflect.Type
+		SrcValue   interface{}
+		Expected   interface{}
+		ShouldFail bool
+	}{
+		{Name: "IntSliceToStringSlice", DestType: reflect.TypeOf([]string{}), SrcValue: []int{1, 2, 3}, Expected: []string{"1", "2", "3"}},
+		{Name: "StringSliceToIntSlice", DestType: reflect.TypeOf([]int{}), SrcValue: []string{"4", "5"}, Expected: []int{4, 5}},
+		{Name: "NilSlice", DestType: reflect.TypeOf([]string{}), SrcValue: []int(nil), Expected: []string(nil)},
+		{Name: "ArrayToSlice", DestType: reflect.TypeOf([]string{}), SrcValue: [2]bool{true, false}, Expected: []string{"true", "false"}},
+		{Name: "SliceToArray", DestType: reflect.TypeOf([3]int{}), SrcValue: []string{"7", "8"}, Expected: [3]int{7, 8, 0}},
+		{Name: "SliceToShortArray", DestType: reflect.TypeOf([1]int{}), SrcValue: []string{"7", "8"}, ShouldFail: true},
+		{Name: "InvalidElement", DestType: reflect.TypeOf([]int{}), SrcValue: []string{"7", "x"}, ShouldFail: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			result, err := convertToList(reflect.New(tc.DestType).Elem(), reflect.ValueOf(tc.SrcValue), &config{})
+
+			if tc.ShouldFail {
+				if err == nil {
+					t.Error("Expected an error, but got none.")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+
+			if result := result.Interface(); !reflect.DeepEqual(result, tc.Expected) {
+				t.Errorf("Unexpected result. Got: %#v, Expected: %#v", result, tc.Expected)
+			}
+		})
+	}
+}
+
+type SrcItem struct {
+	Code  int `kopcup-alias:"ID"`
+	Price string
+}
+
+type DestItem struct {
+	ID    string
+	Price float64
+}
+
+type SourceList struct {
+	Items []SrcItem
+	Tags  [2]int
+	Flags []bool
+}
+
+type DestinationList struct {
+	Items []DestItem
+	Tags  []string
+	Flags [3]string
+}
+
+func TestCopyFromList(t *testing.T) {
+	src := SourceList{
+		Items: []SrcItem{{Code: 1, Price: "100.5"}, {Code: 2, Price: "20"}},
+		Tags:  [2]int{10, 20},
+		Flags: []bool{true},
+	}
+	dest := DestinationList{}
+
+	if err := CopyFrom(&dest, &src); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+
+	expected := DestinationList{
+		Items: []DestItem{{ID: "1", Price: 100.5}, {ID: "2", Price: 20}},
+		Tags:  []string{"10", "20"},
+		Flags: [3]string{"true", "", ""},
+	}
+	if !reflect.DeepEqual(dest, expected) {
+		t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", dest, expected)
+	}
+}
+
+type SourcePointer struct {
+	Name    *string
+	Age     int
+	Score   *int
+	Memo    *string `kopcup-nil:"skip"`
+	Address *SrcAddress
+	Home    SrcAddress
+	Same    *int
+}
+
+type DestinationPointer struct {
+	Name    string
+	Age     *int
+	Score   *string
+	Memo    string
+	Address DestAddress
+	Home    *DestAddress
+	Same    *int
+}
+
+type SourceNilError struct {
+	Name *string `kopcup-nil:"error"`
+}
+
+type SourceNilInvalid struct {
+	Name *string `kopcup-nil:"invalid"`
+}
+
+func TestCopyFromPointer(t *testing.T) {
+	name := "taro"
+	score := 80
+	same := 1
+	age := 42
+
+	testCases := []struct {
+		Name     string
+		Src      SourcePointer
+		Dest     DestinationPointer
+		Expected DestinationPointer
+	}{
+		{
+			Name:     "Dereference",
+			Src:      SourcePointer{Name: &name, Score: &score, Address: &SrcAddress{City: "Tokyo", Building: 1}, Home: SrcAddress{City: "Osaka"}, Same: &same},
+			Expected: DestinationPointer{Name: "taro", Age: new(int), Score: strPtr("80"), Address: DestAddress{City: "Tokyo", Building: "1", MovedAt: "0001/01/01"}, Home: &DestAddress{City: "Osaka", Building: "0", MovedAt: "0001/01/01"}, Same: &same},
+		},
+		{
+			Name:     "Allocate",
+			Src:      SourcePointer{Age: 42},
+			Dest:     DestinationPointer{Name: "keep", Memo: "keep", Address: DestAddress{City: "keep"}, Home: &DestAddress{Note: "keep"}},
+			Expected: DestinationPointer{Age: &age, Memo: "keep", Home: &DestAddress{Building: "0", MovedAt: "0001/01/01", Note: "keep"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			src := tc.Src
+			dest := tc.Dest
+			if err := CopyFrom(&dest, &src); err != nil {
+				t.Fatalf("CopyFrom failed: %v", err)
+			}
+
+			if !reflect.DeepEqual(dest, tc.Expected) {
+				t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", dest, tc.Expected)
+			}
+		})
+	}
+
+	t.Run("NoAliasing", func(t *testing.T) {
+		home := &DestAddress{Note: "keep"}
+		dest := DestinationPointer{Home: home}
+		if err := CopyFrom(&dest, &SourcePointer{Home: SrcAddress{City: "Osaka"}}); err != nil {
+			t.Fatalf("CopyFrom failed: %v", err)
+		}
+		if home.City != "" {
+			t.Errorf("Existing pointee was modified: %+v", home)
+		}
+	})
+
+	t.Run("NilError", func(t *testing.T) {
+		dest := DestinationPointer{}
+		if err := CopyFrom(&dest, &SourceNilError{}); !errors.Is(err, ErrNilPointer) {
+			t.Errorf("Unexpected error. Got: %v, Expected: %v", err, ErrNilPointer)
+		}
+	})
+
+	t.Run("NilInvalid", func(t *testing.T) {
+		dest := DestinationPointer{}
+		if err := CopyFrom(&dest, &SourceNilInvalid{}); err == nil {
+			t.Error("Expected an error, but got none.")
+		}
+	})
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestConvertToUint(t *testing.T) {
+	testCases := []struct {
+		Input    reflect.Value
+		Expected uint64
+		Err      error
+	}{
+		{Input: reflect.ValueOf("42"), Expected: 42},
+		{Input: reflect.ValueOf(true), Expected: 1},
+		{Input: reflect.ValueOf(int16(16)), Expected: 16},
+		{Input: reflect.ValueOf(uint64(math.MaxUint64)), Expected: math.MaxUint64},
+		{Input: reflect.ValueOf(2.0), Expected: 2},
+		{Input: reflect.ValueOf(-1), Err: ErrSignLoss},
+		{Input: reflect.ValueOf("-1"), Err: ErrSignLoss},
+		{Input: reflect.ValueOf(-0.5), Err: ErrSignLoss},
+		{Input: reflect.ValueOf("18446744073709551616"), Err: ErrOverflow},
+		{Input: reflect.ValueOf(1e20), Err: ErrOverflow},
+		{Input: reflect.ValueOf(0.5), Err: ErrTruncated},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Input.Type().String(), func(t *testing.T) {
+			result, err := convertToUint(tc.Input)
+
+			if !errors.Is(err, tc.Err) {
+				t.Errorf("Unexpected error. Got: %v, Expected: %v", err, tc.Err)
+			}
+
+			if result != tc.Expected {
+				t.Errorf("Unexpected result. Got: %v, Expected: %v", result, tc.Expected)
+			}
+		})
+	}
+}
+
+type SourceNumeric struct {
+	Int8    int8
+	Int64   int64
+	Uint    uint
+	Float32 float32
+	Float64 float64
+	String  string
+}
+
+type DestinationNumeric struct {
+	Int8    int64
+	Int64   int
+	Uint    uint64
+	Float32 float64
+	Float64 float32
+	String  uint16
+}
+
+func TestCopyFromNumeric(t *testing.T) {
+	src := SourceNumeric{Int8: -8, Int64: 64, Uint: 1, Float32: 0.5, Float64: 1.5, String: "16"}
+	dest := DestinationNumeric{}
+
+	if err := CopyFrom(&dest, &src); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+
+	expected := DestinationNumeric{Int8: -8, Int64: 64, Uint: 1, Float32: 0.5, Float64: 1.5, String: 16}
+	if !reflect.DeepEqual(dest, expected) {
+		t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", dest, expected)
+	}
+}
+
+type LocalTime time.Time
+
+func TestCopyFromToStruct(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Src  interface{}
+	}{
+		{Name: "IntToStruct", Src: &struct{ X int }{X: 5}},
+		{Name: "StringToStruct", Src: &struct{ X string }{X: "5 JPY"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var dest struct{ X Money }
+			err := CopyFrom(&dest, tc.Src)
+			if !errors.Is(err, ErrUnsupported) {
+				t.Errorf("Expected ErrUnsupported, but got %v", err)
+			}
+		})
+	}
+
+	t.Run("ConvertibleFromTime", func(t *testing.T) {
+		var dest struct{ X LocalTime }
+		if err := CopyFrom(&dest, &struct{ X int64 }{X: 1700000000}); err != nil {
+			t.Fatalf("CopyFrom failed: %v", err)
+		}
+		if !time.Time(dest.X).Equal(time.Unix(1700000000, 0)) {
+			t.Errorf("Unexpected result. Got: %v", time.Time(dest.X))
+		}
+	})
+}
+
+func TestConvertDestToSrcTypeNarrowing(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		DestValue interface{}
+		SrcValue  interface{}
+		Expected  interface{}
+		Err       error
+	}{
+		{Name: "IntToInt8", DestValue: int8(0), SrcValue: 127, Expected: int8(127)},
+		{Name: "IntToInt8Overflow", DestValue: int8(0), SrcValue: 128, Err: ErrOverflow},
+		{Name: "IntToUint", DestValue: uint(0), SrcValue: -1, Err: ErrSignLoss},
+		{Name: "Float64ToFloat32", DestValue: float32(0), SrcValue: math.MaxFloat64, Err: ErrOverflow},
+		{Name: "StringToUint16", DestValue: uint16(0), SrcValue: "65536", Err: ErrOverflow},
+		{Name: "Uint64ToInt", DestValue: 0, SrcValue: uint64(math.MaxUint64), Err: ErrOverflow},
+		{Name: "Float64ToInt32", DestValue: int32(0), SrcValue: 1.5, Err: ErrTruncated},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			destField := reflect.New(reflect.TypeOf(tc.DestValue)).Elem()
+			result, err := convertDestToSrcType(destField, reflect.ValueOf(tc.SrcValue), &config{})
+
+			if !errors.Is(err, tc.Err) {
+				t.Errorf("Unexpected error. Got: %v, Expected: %v", err, tc.Err)
+			}
+			if err != nil {
+				return
+			}
+
+			if result := result.Interface(); !reflect.DeepEqual(result, tc.Expected) {
+				t.Errorf("Unexpected result. Got: %v, Expected: %v", result, tc.Expected)
+			}
+		})
+	}
+}
+
+func newBenchmarkSource() SourceStruct {
+	tm := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	return SourceStruct{
+		StringField: "test", IntField: 42, TimeField: tm,
+		StringField1: "test", IntField1: 42, TimeField1: tm,
+		StringField2: "test", IntField2: 42, TimeField2: tm,
+		StringField3: "test", IntField3: 42, TimeField3: tm,
+		StringField4: "test", IntField4: 42, TimeField4: tm,
+		StringField5: "test", IntField5: 42, TimeField5: tm,
+		StringField6: "test", IntField6: 42, TimeField6: tm,
+	}
+}
+
+func BenchmarkCopyFrom(b *testing.B) {
+	src := newBenchmarkSource()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dest := DestinationStruct{}
+		if err := CopyFrom(&dest, &src); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCopyFromConvert(b *testing.B) {
+	src := SourceStruct2{FieldString: "TestString", FieldInt: 42, FieldBool: "false", FieldTime: time.Now(), Float: "3.14"}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dest := DestinationStruct2{}
+		if err := CopyFrom(&dest, &src, tFmt.RFC3339); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCopyFromNested(b *testing.B) {
+	moved := time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)
+	src := SourceNested{
+		Name: "taro",
+		Contact: SrcContact{
+			Phone:   "03-0000-0000",
+			Address: SrcAddress{Zip: "100-0001", City: "Chiyoda", Building: 12, MovedAt: moved},
+		},
+		Home: SrcAddress{Zip: "530-0001", City: "Kita", Building: 3, MovedAt: moved},
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dest := DestinationNested{}
+		if err := CopyFrom(&dest, &src); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCopyFromParallel(b *testing.B) {
+	src := newBenchmarkSource()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			dest := DestinationStruct{}
+			if err := CopyFrom(&dest, &src); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestCopy(t *testing.T) {
+	now := time.Now()
+	src := SourceStruct2{FieldString: "TestString", FieldInt: 42, FieldBool: "False", FieldTime: now, Float: "3.14"}
+	expected := DestinationStruct2{AliasString: "TestString", AliasInt: 42, AliasBool: false, AliasTime: now, Float: 3.14}
+
+	t.Run("Value", func(t *testing.T) {
+		dest, err := Copy[DestinationStruct2](src, tFmt.RFC3339)
+		if err != nil {
+			t.Fatalf("Copy failed: %v", err)
+		}
+		if !reflect.DeepEqual(dest, expected) {
+			t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", dest, expected)
+		}
+	})
+
+	t.Run("Pointer", func(t *testing.T) {
+		dest, err := Copy[DestinationStruct2](&src, tFmt.RFC3339)
+		if err != nil {
+			t.Fatalf("Copy failed: %v", err)
+		}
+		if !reflect.DeepEqual(dest, expected) {
+			t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", dest, expected)
+		}
+	})
+
+	t.Run("Into", func(t *testing.T) {
+		dest := DestinationStruct2{}
+		if err := CopyInto(&dest, SourceStruct2{FieldInt: 7, FieldBool: "true", Float: "2"}); err != nil {
+			t.Fatalf("CopyInto failed: %v", err)
+		}
+		if !dest.AliasBool || dest.Float != 2 || dest.AliasInt != 7 {
+			t.Errorf("Unexpected result. Got: %+v", dest)
+		}
+	})
+
+	t.Run("Interface", func(t *testing.T) {
+		for _, v := range []interface{}{src, &src} {
+			dest, err := Copy[DestinationStruct2](v, tFmt.RFC3339)
+			if err != nil {
+				t.Fatalf("Copy failed: %v", err)
+			}
+			if !reflect.DeepEqual(dest, expected) {
+				t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", dest, expected)
+			}
+		}
+	})
+}
+
+func TestCopyInvalidArgument(t *testing.T) {
+	var nilSrc *SourceStruct2
+	var nilDest *DestinationStruct2
+
+	testCases := []struct {
+		Name string
+		Copy func() error
+	}{
+		{Name: "GenericDestNotStruct", Copy: func() error { _, err := Copy[int](SourceStruct2{}); return err }},
+		{Name: "GenericSrcNotStruct", Copy: func() error { _, err := Copy[DestinationStruct2]("src"); return err }},
+		{Name: "GenericSrcInterfaceNotStruct", Copy: func() error { var v interface{} = "src"; _, err := Copy[DestinationStruct2](v); return err }},
+		{Name: "GenericSrcInterfaceNil", Copy: func() error { var v interface{}; _, err := Copy[DestinationStruct2](v); return err }},
+		{Name: "GenericSrcNilPointer", Copy: func() error { _, err := Copy[DestinationStruct2](nilSrc); return err }},
+		{Name: "GenericDestNilPointer", Copy: func() error { return CopyInto(nilDest, SourceStruct2{}) }},
+		{Name: "DestNotPointer", Copy: func() error { return CopyFrom(DestinationStruct2{}, &SourceStruct2{}) }},
+		{Name: "DestNilPointer", Copy: func() error { return CopyFrom(nilDest, &SourceStruct2{}) }},
+		{Name: "DestNotStruct", Copy: func() error { i := 0; return CopyFrom(&i, &SourceStruct2{}) }},
+		{Name: "DestNil", Copy: func() error { return CopyFrom(nil, &SourceStruct2{}) }},
+		{Name: "SrcNilPointer", Copy: func() error { return CopyFrom(&DestinationStruct2{}, nilSrc) }},
+		{Name: "SrcNotStruct", Copy: func() error { return CopyFrom(&DestinationStruct2{}, []int{1}) }},
+		{Name: "MapDestNotPointer", Copy: func() error { return CopyFromMap(DestinationStruct2{}, map[string]interface{}{}) }},
+		{Name: "MapSrcNotStruct", Copy: func() error { return CopyToMap(map[string]interface{}{}, 1) }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			if err := tc.Copy(); !errors.Is(err, ErrInvalidArgument) {
+				t.Errorf("Unexpected error. Got: %v, Expected: %v", err, ErrInvalidArgument)
+			}
+		})
+	}
+}
+
+func TestCopyFromCustomLayout(t *testing.T) {
+	if _, err := tFmt.Register("MinuteSlash", "2006/01/02 15:04"); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	src := struct {
+		Named  string `kopcup-dateformat:"MinuteSlash"`
+		Layout string `kopcup-dateformat:"2006-01-02T15:04:05.000"`
+	}{Named: "2024/04/01 09:30", Layout: "2024-04-01T09:30:00.250"}
+	var dest struct {
+		Named  time.Time
+		Layout time.Time
+	}
+
+	if err := CopyFrom(&dest, &src, WithLocation(time.UTC)); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if expected := time.Date(2024, 4, 1, 9, 30, 0, 0, time.UTC); !dest.Named.Equal(expected) {
+		t.Errorf("Unexpected Named. Got: %v, Expected: %v", dest.Named, expected)
+	}
+	if expected := time.Date(2024, 4, 1, 9, 30, 0, 250000000, time.UTC); !dest.Layout.Equal(expected) {
+		t.Errorf("Unexpected Layout. Got: %v, Expected: %v", dest.Layout, expected)
+	}
+}
+
+func TestCopyFromLateRegister(t *testing.T) {
+	type source struct {
+		At string `kopcup-dateformat:"LateRegistered"`
+	}
+	var dest struct{ At time.Time }
+
+	src := source{At: "2024/04/01 09:30"}
+	if err := CopyFrom(&dest, &src, WithLocation(time.UTC)); err == nil {
+		t.Fatal("Expected an error before registration, but got none.")
+	}
+
+	if _, err := tFmt.Register("LateRegistered", "2006/01/02 15:04"); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := CopyFrom(&dest, &src, WithLocation(time.UTC)); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if expected := time.Date(2024, 4, 1, 9, 30, 0, 0, time.UTC); !dest.At.Equal(expected) {
+		t.Errorf("Unexpected At. Got: %v, Expected: %v", dest.At, expected)
+	}
+
+	if _, err := tFmt.Register("LateRegistered", "2006-01-02"); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := CopyFrom(&dest, &source{At: "2024-04-02"}, WithLocation(time.UTC)); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if expected := time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC); !dest.At.Equal(expected) {
+		t.Errorf("Unexpected At. Got: %v, Expected: %v", dest.At, expected)
+	}
+}
+
+func TestCopyFromFallbackLayouts(t *testing.T) {
+	type source struct {
+		Date string `kopcup-dateformat:"2006/01/02|20060102|2006-01-02T15:04:05Z07:00"`
+	}
+	type destination struct {
+		Date time.Time
+	}
+	expected := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	for _, input := range []string{"2024/01/02", "20240102", "2024-01-02T00:00:00Z"} {
+		t.Run(input, func(t *testing.T) {
+			var dest destination
+			if err := CopyFrom(&dest, &source{Date: input}, WithLocation(time.UTC)); err != nil {
+				t.Fatalf("CopyFrom failed: %v", err)
+			}
+			if !dest.Date.Equal(expected) {
+				t.Errorf("Unexpected result. Got: %v, Expected: %v", dest.Date, expected)
+			}
+		})
+	}
+
+	t.Run("Option", func(t *testing.T) {
+		var dest destination
+		src := struct{ Date string }{Date: "20240102"}
+		if err := CopyFrom(&dest, &src, WithTimeFormats(tFmt.DateOnlyB, tFmt.DateOnlyBlock), WithLocation(time.UTC)); err != nil {
+			t.Fatalf("CopyFrom failed: %v", err)
+		}
+		if !dest.Date.Equal(expected) {
+			t.Errorf("Unexpected result. Got: %v, Expected: %v", dest.Date, expected)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		var dest destination
+		err := CopyFrom(&dest, &source{Date: "02.01.2024"})
+		if err == nil {
+			t.Fatal("Expected an error, but got none.")
+		}
+		for _, layout := range []string{"2006/01/02", "20060102", "2006-01-02T15:04:05Z07:00"} {
+			if !strings.Contains(err.Error(), layout) {
+				t.Errorf("Expected error to contain %q, but got %v", layout, err)
+			}
+		}
+	})
+
+	t.Run("Format", func(t *testing.T) {
+		var dest struct{ Date string }
+		src := struct {
+			Date time.Time `kopcup-dateformat:"2006/01/02|20060102"`
+		}{Date: expected}
+		if err := CopyFrom(&dest, &src); err != nil {
+			t.Fatalf("CopyFrom failed: %v", err)
+		}
+		if dest.Date != "2024/01/02" {
+			t.Errorf("Unexpected result. Got: %v, Expected: %v", dest.Date, "2024/01/02")
+		}
+	})
+}
+
+func TestCopyFromWareki(t *testing.T) {
+	type wareki struct {
+		Kanji  string `kopcup-dateformat:"WarekiKanji"`
+		Letter string `kopcup-dateformat:"Gyy.MM.dd"`
+	}
+	type gregorian struct {
+		Kanji  time.Time `kopcup-dateformat:"WarekiKanji"`
+		Letter time.Time `kopcup-dateformat:"WarekiLetter"`
+	}
+	date := time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	var dest gregorian
+	if err := CopyFrom(&dest, &wareki{Kanji: "令和元年5月1日", Letter: "R01.05.01"}, WithLocation(time.UTC)); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if !dest.Kanji.Equal(date) || !dest.Letter.Equal(date) {
+		t.Errorf("Unexpected result. Got: %+v, Expected: %v", dest, date)
+	}
+
+	var back wareki
+	if err := CopyFrom(&back, &gregorian{Kanji: date, Letter: date}); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if expected := (wareki{Kanji: "令和元年5月1日", Letter: "R01.05.01"}); back != expected {
+		t.Errorf("Unexpected result. Got: %+v, Expected: %+v", back, expected)
+	}
+
+	if err := CopyFrom(&back, &gregorian{Kanji: time.Date(1800, 1, 1, 0, 0, 0, 0, time.UTC)}); err == nil {
+		t.Error("Expected an error for a date before Meiji, but got none.")
+	}
+
+	// タグがコピー先の time.Time の項目のみにある場合
+	type entity struct {
+		Birthday time.Time `kopcup-dateformat:"WarekiKanji"`
+	}
+	type dto struct {
+		Birthday string
+	}
+	var e entity
+	if err := CopyFrom(&e, &dto{Birthday: "平成31年4月30日"}, WithLocation(time.UTC)); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if expected := time.Date(2019, 4, 30, 0, 0, 0, 0, time.UTC); !e.Birthday.Equal(expected) {
+		t.Errorf("Unexpected result. Got: %v, Expected: %v", e.Birthday, expected)
+	}
+	var d dto
+	if err := CopyFrom(&d, &e); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if d.Birthday != "平成31年4月30日" {
+		t.Errorf("Unexpected result. Got: %q", d.Birthday)
+	}
+}
@@ -0,0 +1,306 @@
+package kop2cup
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+/*
+map[string]interface{} の値を構造体の同一名称または同一タグの項目へコピーします。
+  - &dest コピー先ポインタ
+  - src コピー元マップ（キーは別名（WithTagKeys のタグ、省略時は kopcup-alias、json、db の順）、なければ項目名）
+  - opts 動作の指定（CopyFrom と同じ）
+  - 値の変換には CopyFrom と同じ規則を適用
+  - ネストした構造体には map[string]interface{}、スライス・配列には []interface{} を指定可能
+  - 値が nil のキー、WithMerge の場合に設定されていない値のキーは無視
+  - ポインタ型の項目は領域を確保して値を格納
+  - タグ kopcup:"-" の項目はコピーしない、kopcup:"omitempty" の項目は値がゼロ値の場合にコピーしない
+  - WithNameMatcher の場合、同名のキーがなければ正規化した名前が同じキー（複数ある場合は ErrAmbiguousName）
+  - WithStrict の場合、どの項目にも対応しないキーは ErrUnmatched
+*/
+func CopyFromMap(dest interface{}, src map[string]interface{}, opts ...Option) error {
+	destValue, err := destStructValue(dest)
+	if err != nil {
+		return err
+	}
+	c, err := newConfig(opts...)
+	if err != nil {
+		return err
+	}
+	return copyFromMap(destValue, src, c)
+}
+
+/*
+構造体の項目を map[string]interface{} へコピーします。
+  - dest コピー先マップ
+  - &src コピー元ポインタ
+  - opts 動作の指定（WithTimeFormat は time.Time を文字列へ変換する場合のデフォルト日付フォーマット）
+  - キーは kopcup-alias の値、なければ項目名（WithTagKeys を指定した場合はそのタグから探した別名）
+  - タグ kopcup:"-" の項目は含めない、kopcup:"omitempty" の項目はゼロ値の場合に含めない
+  - time.Time は kopcup-epoch または WithEpochUnit が指定されている場合は Unix 時間（int64）に変換
+  - time.Time は kopcup-dateformat または日付フォーマットが指定されている場合のみ文字列に変換
+  - ネストした構造体（ポインタを含む）は map[string]interface{}、その要素を持つスライス・配列は []interface{} に変換
+*/
+func CopyToMap(dest map[string]interface{}, src interface{}, opts ...Option) error {
+	if dest == nil {
+		return fmt.Errorf("%w: dest map is nil", ErrInvalidArgument)
+	}
+	srcValue, err := srcStructValue(src)
+	if err != nil {
+		return err
+	}
+	c, err := newConfig(opts...)
+	if err != nil {
+		return err
+	}
+	return copyToMap(dest, srcValue, c)
+}
+
+func copyFromMap(destValue reflect.Value, src map[string]interface{}, c *config) error {
+	destType := destValue.Type()
+	used := map[string]bool{}
+	var all FieldErrors
+	var keys map[string][]string
+	if names := c.plan.names; names != nil {
+		keys = map[string][]string{}
+		for key := range src {
+			keys[names.normalize(key)] = append(keys[names.normalize(key)], key)
+		}
+	}
+	for i := 0; i < destValue.NumField(); i++ {
+		field := destType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key := fieldAlias(field, c.plan.aliasKeys())
+		v, ok := src[key]
+		if !ok {
+			key = field.Name
+			v, ok = src[key]
+		}
+		if !ok && keys != nil {
+			// NameMatcher で正規化したキーが同じ項目
+			switch matched := keys[c.plan.names.normalize(field.Name)]; len(matched) {
+			case 0:
+			case 1:
+				key = matched[0]
+				v, ok = src[key]
+			default:
+				sort.Strings(matched)
+				err := newFieldError(field.Name, field.Type, reflect.ValueOf(src[matched[0]]), fmt.Errorf("%w: keys %q and %q", ErrAmbiguousName, matched[0], matched[1]))
+				for _, k := range matched {
+					used[k] = true
+				}
+				if c.errorMode != CollectAll {
+					return err
+				}
+				all = appendFieldErrors(all, err)
+				continue
+			}
+		}
+		if !ok {
+			continue
+		}
+		used[key] = true
+		if v == nil || !c.isSet(reflect.ValueOf(v)) {
+			continue
+		}
+
+		if err := copyMapField(destValue.Field(i), field, v, c); err != nil {
+			if c.errorMode != CollectAll {
+				return err
+			}
+			all = appendFieldErrors(all, err)
+		}
+	}
+	if c.strict {
+		for key, v := range src {
+			if used[key] {
+				continue
+			}
+			err := newFieldError(key, nil, reflect.ValueOf(v), ErrUnmatched)
+			if c.errorMode != CollectAll {
+				return err
+			}
+			all = appendFieldErrors(all, err)
+		}
+	}
+	if len(all) != 0 {
+		return all
+	}
+	return nil
+}
+
+/*
+マップの値を1項目へコピーします。
+*/
+func copyMapField(destField reflect.Value, field reflect.StructField, v interface{}, c *config) error {
+	tag, err := parseKopcupTag(field.Tag.Get("kopcup"))
+	if err != nil {
+		return newFieldError(field.Name, field.Type, reflect.ValueOf(v), err)
+	}
+	if tag.ignore || tag.omitEmpty && reflect.ValueOf(v).IsZero() {
+		return nil
+	}
+	tags, err := fieldTags(field)
+	if err != nil {
+		return newFieldError(field.Name, field.Type, reflect.ValueOf(v), err)
+	}
+	fc, err := c.override(tags)
+	if err != nil {
+		return newFieldError(field.Name, field.Type, reflect.ValueOf(v), err)
+	}
+	converted, err := convertMapValue(destField, v, fc)
+	if converted.IsValid() {
+		destField.Set(converted)
+	}
+	if err != nil {
+		return newFieldError(field.Name, field.Type, reflect.ValueOf(v), err)
+	}
+	return nil
+}
+
+/*
+マップの値をコピー先項目の型へ変換します。
+*/
+func convertMapValue(destField reflect.Value, v interface{}, c *config) (reflect.Value, error) {
+	if destField.Kind() == reflect.Ptr && reflect.TypeOf(v) != destField.Type() {
+		ptr := reflect.New(destField.Type().Elem())
+		if !destField.IsNil() {
+			ptr.Elem().Set(destField.Elem())
+		}
+		converted, err := convertMapValue(ptr.Elem(), v, c)
+		if !converted.IsValid() {
+			return reflect.Value{}, err
+		}
+		ptr.Elem().Set(converted)
+		return ptr, err
+	}
+	if m, ok := v.(map[string]interface{}); ok && isNestedStruct(destField.Type()) {
+		nested := reflect.New(destField.Type()).Elem()
+		nested.Set(destField)
+		if err := copyFromMap(nested, m, c); err != nil {
+			if c.errorMode != CollectAll {
+				return reflect.Value{}, err
+			}
+			return nested, err
+		}
+		return nested, nil
+	}
+	if l, ok := v.([]interface{}); ok && isList(destField.Type()) {
+		destType := destField.Type()
+		var list reflect.Value
+		if destType.Kind() == reflect.Slice {
+			list = reflect.MakeSlice(destType, len(l), len(l))
+		} else {
+			if len(l) > destType.Len() {
+				return reflect.Value{}, fmt.Errorf("%w: %d elements to %s", ErrOverflow, len(l), destType)
+			}
+			list = reflect.New(destType).Elem()
+		}
+		errs := make([]error, len(l))
+		for i, e := range l {
+			if e == nil {
+				continue
+			}
+			converted, err := convertMapValue(list.Index(i), e, c)
+			if converted.IsValid() {
+				list.Index(i).Set(converted)
+			}
+			if err != nil {
+				errs[i] = newFieldError(fmt.Sprintf("[%d]", i), destType.Elem(), reflect.ValueOf(e), err)
+				if c.errorMode != CollectAll {
+					break
+				}
+			}
+		}
+		if err := c.result(errs); err != nil {
+			if c.errorMode != CollectAll {
+				return reflect.Value{}, err
+			}
+			return list, err
+		}
+		return list, nil
+	}
+	return convertDestToSrcType(destField, reflect.ValueOf(v), c)
+}
+
+func copyToMap(dest map[string]interface{}, srcValue reflect.Value, c *config) error {
+	srcType := srcValue.Type()
+	// 既存の呼び出しのキーを変えないよう、WithTagKeys の指定がなければ kopcup-alias のみ
+	keys := []string{"kopcup-alias"}
+	if c.customTagKeys {
+		keys = c.plan.aliasKeys()
+	}
+	for i := 0; i < srcValue.NumField(); i++ {
+		field := srcType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key := field.Name
+		if alias := fieldAlias(field, keys); alias != "" {
+			key = alias
+		}
+
+		tag, err := parseKopcupTag(field.Tag.Get("kopcup"))
+		if err != nil {
+			return newFieldError(field.Name, reflect.TypeOf(dest), srcValue.Field(i), err)
+		}
+		if tag.ignore || tag.omitEmpty && srcValue.Field(i).IsZero() {
+			continue
+		}
+		tags, err := fieldTags(field)
+		if err != nil {
+			return newFieldError(field.Name, reflect.TypeOf(dest), srcValue.Field(i), err)
+		}
+		fc, err := c.override(tags)
+		if err != nil {
+			return newFieldError(field.Name, reflect.TypeOf(dest), srcValue.Field(i), err)
+		}
+		v, err := convertToMapValue(srcValue.Field(i), fc)
+		if err != nil {
+			return newFieldError(field.Name, reflect.TypeOf(dest), srcValue.Field(i), err)
+		}
+		dest[key] = v
+	}
+	return nil
+}
+
+/*
+構造体の項目をマップの値へ変換します。
+*/
+func convertToMapValue(srcField reflect.Value, c *config) (interface{}, error) {
+	switch {
+	case srcField.Kind() == reflect.Ptr && isNestedStruct(srcField.Type().Elem()):
+		if srcField.IsNil() {
+			return nil, nil
+		}
+		return convertToMapValue(srcField.Elem(), c)
+	case srcField.Type() == reflect.TypeOf(time.Time{}) && c.epoch > 0:
+		return timeToEpoch(srcField.Interface().(time.Time), c.epoch), nil
+	case srcField.Type() == reflect.TypeOf(time.Time{}) && len(c.tfmts) != 0:
+		return convertToString(srcField, c.tfmts...)
+	case isNestedStruct(srcField.Type()):
+		m := map[string]interface{}{}
+		if err := copyToMap(m, srcField, c); err != nil {
+			return nil, err
+		}
+		return m, nil
+	case isList(srcField.Type()) && isNestedStruct(srcField.Type().Elem()):
+		if srcField.Kind() == reflect.Slice && srcField.IsNil() {
+			return []interface{}(nil), nil
+		}
+		l := make([]interface{}, srcField.Len())
+		for i := range l {
+			v, err := convertToMapValue(srcField.Index(i), c)
+			if err != nil {
+				return nil, newFieldError(fmt.Sprintf("[%d]", i), reflect.TypeOf(l).Elem(), srcField.Index(i), err)
+			}
+			l[i] = v
+		}
+		return l, nil
+	}
+	return srcField.Interface(), nil
+}
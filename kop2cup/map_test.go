@@ -0,0 +1,238 @@
+package kop2cup
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type MapAddress struct {
+	City string `kopcup-alias:"city"`
+	Zip  int    `kopcup-alias:"zip"`
+}
+
+type MapStruct struct {
+	Name     string     `kopcup-alias:"name"`
+	Age      int        `kopcup-alias:"age"`
+	Active   bool       `kopcup-alias:"active"`
+	Score    float64    `kopcup-alias:"score"`
+	Birthday time.Time  `kopcup-alias:"birthday" kopcup-dateformat:"2006/01/02"`
+	Address  MapAddress `kopcup-alias:"address"`
+	Tags     []string   `kopcup-alias:"tags"`
+	History  []MapAddress
+	Office   *MapAddress `kopcup-alias:"office"`
+	Nickname *string     `kopcup-alias:"nickname"`
+	Note     string
+	secret   string
+}
+
+func TestCopyFromMap(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		Src        map[string]interface{}
+		Dest       MapStruct
+		Expected   MapStruct
+		ShouldFail bool
+	}{
+		{
+			Name: "CopyFields",
+			Src: map[string]interface{}{
+				"name":     "taro",
+				"age":      "42",
+				"active":   "true",
+				"score":    "3.5",
+				"birthday": "2000/01/02",
+				"address":  map[string]interface{}{"city": "Tokyo", "zip": "1000001"},
+				"tags":     []interface{}{"a", true, 3},
+				"History":  []interface{}{map[string]interface{}{"city": "Osaka"}, nil},
+				"office":   map[string]interface{}{"city": "Nagoya"},
+				"nickname": "taro",
+				"Note":     nil,
+				"secret":   "ignored",
+				"unknown":  "ignored",
+			},
+			Dest: MapStruct{Note: "keep"},
+			Expected: MapStruct{
+				Name:     "taro",
+				Age:      42,
+				Active:   true,
+				Score:    3.5,
+				Birthday: time.Date(2000, 1, 2, 0, 0, 0, 0, time.FixedZone("JST", 9*60*60)),
+				Address:  MapAddress{City: "Tokyo", Zip: 1000001},
+				Tags:     []string{"a", "true", "3"},
+				History:  []MapAddress{{City: "Osaka"}, {}},
+				Office:   &MapAddress{City: "Nagoya"},
+				Nickname: strPtr("taro"),
+				Note:     "keep",
+			},
+		},
+		{
+			Name:     "FieldNameFallback",
+			Src:      map[string]interface{}{"Name": "hanako", "Age": 20},
+			Expected: MapStruct{Name: "hanako", Age: 20},
+		},
+		{
+			Name:     "JSONNumber",
+			Src:      map[string]interface{}{"age": 30.0, "address": map[string]interface{}{"zip": 1000001.0}},
+			Expected: MapStruct{Age: 30, Address: MapAddress{Zip: 1000001}},
+		},
+		{
+			Name:       "InvalidValue",
+			Src:        map[string]interface{}{"age": "invalid"},
+			ShouldFail: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			dest := tc.Dest
+			err := CopyFromMap(&dest, tc.Src)
+
+			if tc.ShouldFail {
+				if err == nil {
+					t.Error("Expected an error, but got none.")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+
+			if !dest.Birthday.Equal(tc.Expected.Birthday) {
+				t.Errorf("Unexpected value for Birthday. Got: %v, Expected: %v", dest.Birthday, tc.Expected.Birthday)
+			}
+			dest.Birthday, tc.Expected.Birthday = time.Time{}, time.Time{}
+			if !reflect.DeepEqual(dest, tc.Expected) {
+				t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", dest, tc.Expected)
+			}
+		})
+	}
+}
+
+func TestCopyToMap(t *testing.T) {
+	birthday := time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)
+	testCases := []struct {
+		Name     string
+		Src      MapStruct
+		Options  []Option
+		Expected map[string]interface{}
+	}{
+		{
+			Name: "CopyFields",
+			Src: MapStruct{
+				Name:     "taro",
+				Age:      42,
+				Birthday: birthday,
+				Address:  MapAddress{City: "Tokyo", Zip: 1000001},
+				Tags:     []string{"a"},
+				History:  []MapAddress{{City: "Osaka"}},
+				Office:   &MapAddress{City: "Nagoya"},
+				secret:   "hidden",
+			},
+			Expected: map[string]interface{}{
+				"name":     "taro",
+				"age":      42,
+				"active":   false,
+				"score":    0.0,
+				"birthday": "2000/01/02",
+				"address":  map[string]interface{}{"city": "Tokyo", "zip": 1000001},
+				"tags":     []string{"a"},
+				"History":  []interface{}{map[string]interface{}{"city": "Osaka", "zip": 0}},
+				"office":   map[string]interface{}{"city": "Nagoya", "zip": 0},
+				"nickname": (*string)(nil),
+				"Note":     "",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			dest := map[string]interface{}{}
+			if err := CopyToMap(dest, &tc.Src, tc.Options...); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(dest, tc.Expected) {
+				t.Errorf("Unexpected result. \n      Got: %#v\n Expected: %#v", dest, tc.Expected)
+			}
+		})
+	}
+
+	if err := CopyToMap(nil, &MapStruct{}); err == nil {
+		t.Error("Expected an error for nil map, but got none.")
+	}
+}
+
+func TestCopyMapKopcupTag(t *testing.T) {
+	src := SourceKopcupTag{ID: 2, Password: "secret", Email: "mail"}
+	dest := map[string]interface{}{}
+	if err := CopyToMap(dest, &src); err != nil {
+		t.Fatalf("CopyToMap failed: %v", err)
+	}
+	expected := map[string]interface{}{"Email": "mail", "Memo": ""}
+	if !reflect.DeepEqual(dest, expected) {
+		t.Errorf("Unexpected result. \n      Got: %#v\n Expected: %#v", dest, expected)
+	}
+
+	back := DestinationKopcupTag{Email: "keep", Memo: "keep"}
+	if err := CopyFromMap(&back, map[string]interface{}{"ID": 3, "Email": "", "Memo": "memo"}, WithStrict()); err != nil {
+		t.Fatalf("CopyFromMap failed: %v", err)
+	}
+	if expectedDest := (DestinationKopcupTag{ID: 3, Email: "keep", Memo: "keep"}); back != expectedDest {
+		t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", back, expectedDest)
+	}
+}
+
+func TestCopyMapTagKeys(t *testing.T) {
+	src := TagResponse{UserID: 1, UserName: "taro", Mail: "taro@example.com", DisplayName: "tarochan"}
+
+	// WithTagKeys の指定がなければ従来どおり項目名（kopcup-alias があればその値）をキーにする
+	dest := map[string]interface{}{}
+	if err := CopyToMap(dest, &src); err != nil {
+		t.Fatalf("CopyToMap failed: %v", err)
+	}
+	expected := map[string]interface{}{"UserID": 1, "UserName": "taro", "Mail": "taro@example.com", "DisplayName": "tarochan"}
+	if !reflect.DeepEqual(dest, expected) {
+		t.Errorf("Unexpected result. \n      Got: %#v\n Expected: %#v", dest, expected)
+	}
+
+	dest = map[string]interface{}{}
+	if err := CopyToMap(dest, &src, WithTagKeys("kopcup-alias", "json")); err != nil {
+		t.Fatalf("CopyToMap failed: %v", err)
+	}
+	expected = map[string]interface{}{"user_id": 1, "user_name": "taro", "mail": "taro@example.com", "display_name": "tarochan"}
+	if !reflect.DeepEqual(dest, expected) {
+		t.Errorf("Unexpected result. \n      Got: %#v\n Expected: %#v", dest, expected)
+	}
+
+	var back TagResponse
+	if err := CopyFromMap(&back, dest, WithStrict()); err != nil {
+		t.Fatalf("CopyFromMap failed: %v", err)
+	}
+	if back != src {
+		t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", back, src)
+	}
+}
+
+func TestCopyFromMapInterfaceField(t *testing.T) {
+	var dest struct {
+		A interface{}
+		B interface{}
+	}
+	if err := CopyFromMap(&dest, map[string]interface{}{"A": "x", "B": 1}); err != nil {
+		t.Fatalf("CopyFromMap failed: %v", err)
+	}
+	if dest.A != "x" || dest.B != 1 {
+		t.Errorf("Unexpected result. Got: %+v", dest)
+	}
+
+	var fromStruct struct{ A interface{} }
+	if err := CopyFrom(&fromStruct, &struct{ A string }{A: "x"}); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if fromStruct.A != "x" {
+		t.Errorf("Unexpected result. Got: %+v", fromStruct)
+	}
+}
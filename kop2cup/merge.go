@@ -0,0 +1,52 @@
+package kop2cup
+
+import (
+	"reflect"
+)
+
+/*
+PATCH のように、コピー元で「設定されている」項目のみコピー先へ上書きする場合の判定方法です。
+*/
+type MergeMode int
+
+const (
+	MergeNone     MergeMode = iota // 全ての項目をコピー（省略時）
+	MergeNonZero                   // ゼロ値でない項目のみコピー
+	MergeNonNil                    // nil でない項目のみコピー（ポインタ・スライス・マップ・インターフェース以外は常にコピー）
+	MergeIsZeroer                  // IsZero() bool を持つ型はその結果、持たない型はゼロ値かどうかで判定
+)
+
+/*
+マージモードを指定します。
+  - 判定は項目ごとに行い、ネストした構造体（同じ型を含む）は項目単位で再帰的にマージ
+  - ポインタの項目は nil でなければ指す先の構造体ごと置き換え
+*/
+func WithMerge(mode MergeMode) Option {
+	return optionFunc(func(c *config) { c.merge = mode })
+}
+
+/*
+マージモードでコピー元の値が「設定されている」場合に true を返します。マージモードでなければ常に true です。
+*/
+func (c *config) isSet(srcField reflect.Value) bool {
+	switch c.merge {
+	case MergeNonZero:
+		return !srcField.IsZero()
+	case MergeNonNil:
+		switch srcField.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+			return !srcField.IsNil()
+		}
+	case MergeIsZeroer:
+		if srcField.Kind() == reflect.Ptr && srcField.IsNil() {
+			return false
+		}
+		if srcField.CanInterface() {
+			if z, ok := srcField.Interface().(interface{ IsZero() bool }); ok {
+				return !z.IsZero()
+			}
+		}
+		return !srcField.IsZero()
+	}
+	return true
+}
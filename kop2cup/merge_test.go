@@ -0,0 +1,117 @@
+package kop2cup
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type Version int
+
+func (v Version) IsZero() bool {
+	return v < 0
+}
+
+type MergeAddress struct {
+	City string
+	Zip  string
+}
+
+type MergeUser struct {
+	Name    string
+	Age     int
+	Email   *string
+	Tags    []string
+	Version Version
+	Address MergeAddress
+	Updated time.Time
+}
+
+type MergePatch struct {
+	Name    string
+	Age     *int
+	Email   *string
+	Tags    []string
+	Version Version
+	Address MergeAddress
+	Updated time.Time
+}
+
+func TestMerge(t *testing.T) {
+	age := 0
+	email := "new@example.com"
+	updated := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	existing := func() MergeUser {
+		old := "old@example.com"
+		return MergeUser{Name: "taro", Age: 40, Email: &old, Tags: []string{"a"}, Version: 3,
+			Address: MergeAddress{City: "Tokyo", Zip: "100"}, Updated: updated.Add(-time.Hour)}
+	}
+
+	testCases := []struct {
+		Name     string
+		Mode     MergeMode
+		Patch    MergePatch
+		Expected func() MergeUser
+	}{
+		{
+			Name:  "NonZero",
+			Mode:  MergeNonZero,
+			Patch: MergePatch{Age: &age, Address: MergeAddress{Zip: "200"}, Updated: updated},
+			Expected: func() MergeUser {
+				u := existing()
+				u.Age, u.Address.Zip, u.Updated = 0, "200", updated
+				return u
+			},
+		},
+		{
+			Name:  "NonNil",
+			Mode:  MergeNonNil,
+			Patch: MergePatch{Email: &email, Address: MergeAddress{City: "Osaka"}},
+			Expected: func() MergeUser {
+				u := existing()
+				u.Name, u.Email, u.Version, u.Address, u.Updated = "", &email, 0, MergeAddress{City: "Osaka"}, time.Time{}
+				return u
+			},
+		},
+		{
+			Name:  "IsZeroer",
+			Mode:  MergeIsZeroer,
+			Patch: MergePatch{Name: "jiro", Version: 0, Tags: []string{}},
+			Expected: func() MergeUser {
+				u := existing()
+				u.Name, u.Version, u.Tags = "jiro", 0, []string{}
+				return u
+			},
+		},
+		{
+			Name:  "IsZeroerSkip",
+			Mode:  MergeIsZeroer,
+			Patch: MergePatch{Version: -1},
+			Expected: func() MergeUser {
+				return existing()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			dest := existing()
+			if err := CopyFrom(&dest, &tc.Patch, WithMerge(tc.Mode)); err != nil {
+				t.Fatalf("CopyFrom failed: %v", err)
+			}
+			if expected := tc.Expected(); !reflect.DeepEqual(dest, expected) {
+				t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", dest, expected)
+			}
+		})
+	}
+}
+
+func TestMergeMap(t *testing.T) {
+	dest := MergeUser{Name: "taro", Age: 40}
+	if err := CopyFromMap(&dest, map[string]interface{}{"Name": "", "Age": 41}, WithMerge(MergeNonZero)); err != nil {
+		t.Fatalf("CopyFromMap failed: %v", err)
+	}
+	if dest.Name != "taro" || dest.Age != 41 {
+		t.Errorf("Unexpected result. Got: %+v", dest)
+	}
+}
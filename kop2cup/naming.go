@@ -0,0 +1,95 @@
+package kop2cup
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+)
+
+/*
+コピー元とコピー先の項目名を対応付ける方法です。
+正規化した名前が同じ項目を同名として扱います。
+  - normalize 項目名を正規化する関数
+  - plans この方法で作成した structPlan（NameMatcher が不要になれば共に解放）
+*/
+type NameMatcher struct {
+	normalize func(string) string
+	plans     sync.Map
+}
+
+/*
+項目名を正規化する関数から NameMatcher を作成します。
+normalize が nil の NameMatcher を WithNameMatcher で指定した場合は ErrInvalidArgument を返します。
+*/
+func NewNameMatcher(normalize func(string) string) *NameMatcher {
+	return &NameMatcher{normalize: normalize}
+}
+
+var (
+	// 大文字・小文字を区別しない（例: UserId と Userid）
+	NameCaseInsensitive = NewNameMatcher(strings.ToLower)
+	// 大文字・小文字と "_"・"-" を区別しない（例: UserId、user_id、user-id）
+	NameSeparatorInsensitive = NewNameMatcher(func(name string) string {
+		return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+	})
+	// 単語の区切りで比較し、略語は1単語として扱う（例: UserID、UserId、user_id）
+	NameAcronymAware = NewNameMatcher(func(name string) string {
+		return strings.Join(splitWords(name), "_")
+	})
+)
+
+/*
+コピー元とコピー先の項目名の対応付けに使う NameMatcher を指定します。
+  - 同名の項目がない場合のみ、正規化した名前が同じ項目を探す
+  - コピー先に該当する項目が複数ある場合や、他のコピー元の項目も同じ名前に正規化される場合は ErrAmbiguousName
+  - 別名（kopcup-alias 等）は正規化せずに比較
+  - CopyFromMap ではマップのキーも同様に対応付け
+  - nil の場合は同名のみで対応付け（省略時と同じ）
+*/
+func WithNameMatcher(m *NameMatcher) Option {
+	return optionFunc(func(c *config) { c.plan.names = m })
+}
+
+/*
+項目名を小文字の単語に分割します。
+"_"・"-"・空白で区切り、大文字の連続は略語として1単語とします（末尾の複数形の "s" を含む。例: "IDs"）。
+*/
+func splitWords(name string) []string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) != 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = nil
+		}
+	}
+	runes := []rune(name)
+	for i, r := range runes {
+		if r == '_' || r == '-' || unicode.IsSpace(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			switch {
+			case unicode.IsLower(prev) || unicode.IsDigit(prev):
+				flush()
+			case unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1]) && !isPluralSuffix(runes, i+1):
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+	return words
+}
+
+/*
+runes[i] 以降が略語の複数形の "s"（直後が終端・区切り・大文字）であれば true を返します。
+*/
+func isPluralSuffix(runes []rune, i int) bool {
+	if runes[i] != 's' {
+		return false
+	}
+	return i+1 == len(runes) || !unicode.IsLower(runes[i+1])
+}
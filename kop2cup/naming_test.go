@@ -0,0 +1,215 @@
+package kop2cup
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitWords(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Expected []string
+	}{
+		{Name: "UserID", Expected: []string{"user", "id"}},
+		{Name: "UserId", Expected: []string{"user", "id"}},
+		{Name: "user_id", Expected: []string{"user", "id"}},
+		{Name: "user-id", Expected: []string{"user", "id"}},
+		{Name: "HTTPServer", Expected: []string{"http", "server"}},
+		{Name: "httpServer", Expected: []string{"http", "server"}},
+		{Name: "UserIDs", Expected: []string{"user", "ids"}},
+		{Name: "Address2City", Expected: []string{"address2", "city"}},
+		{Name: "ID", Expected: []string{"id"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			if words := splitWords(tc.Name); !reflect.DeepEqual(words, tc.Expected) {
+				t.Errorf("Unexpected result. \n      Got: %q\n Expected: %q", words, tc.Expected)
+			}
+		})
+	}
+}
+
+type NamingModel struct {
+	UserId    int
+	Username  string
+	CreatedAt string
+	HTTPPort  int
+}
+
+type NamingDTO struct {
+	UserID     int
+	UserName   string
+	Created_At string
+	HttpPort   int
+}
+
+func TestCopyFromNameMatcher(t *testing.T) {
+	src := NamingModel{UserId: 1, Username: "taro", CreatedAt: "2024-04-01", HTTPPort: 8080}
+
+	testCases := []struct {
+		Name     string
+		Matcher  *NameMatcher
+		Expected NamingDTO
+	}{
+		{
+			Name:     "Exact",
+			Expected: NamingDTO{},
+		},
+		{
+			Name:     "CaseInsensitive",
+			Matcher:  NameCaseInsensitive,
+			Expected: NamingDTO{UserID: 1, UserName: "taro", HttpPort: 8080},
+		},
+		{
+			Name:     "SeparatorInsensitive",
+			Matcher:  NameSeparatorInsensitive,
+			Expected: NamingDTO{UserID: 1, UserName: "taro", Created_At: "2024-04-01", HttpPort: 8080},
+		},
+		{
+			// Username と UserName は単語の区切りが異なるため対応しない
+			Name:     "AcronymAware",
+			Matcher:  NameAcronymAware,
+			Expected: NamingDTO{UserID: 1, Created_At: "2024-04-01", HttpPort: 8080},
+		},
+		{
+			Name:     "Custom",
+			Matcher:  NewNameMatcher(func(name string) string { return strings.TrimSuffix(strings.ToLower(name), "at") }),
+			Expected: NamingDTO{UserID: 1, UserName: "taro", HttpPort: 8080},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var dest NamingDTO
+			var opts []Option
+			if tc.Matcher != nil {
+				opts = append(opts, WithNameMatcher(tc.Matcher))
+			}
+			if err := CopyFrom(&dest, &src, opts...); err != nil {
+				t.Fatalf("CopyFrom failed: %v", err)
+			}
+			if dest != tc.Expected {
+				t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", dest, tc.Expected)
+			}
+		})
+	}
+
+	t.Run("ExactFirst", func(t *testing.T) {
+		src := struct{ UserID, Userid int }{UserID: 1, Userid: 2}
+		var dest struct{ UserID, Userid int }
+		if err := CopyFrom(&dest, &src, WithNameMatcher(NameCaseInsensitive)); err != nil {
+			t.Fatalf("CopyFrom failed: %v", err)
+		}
+		if dest.UserID != 1 || dest.Userid != 2 {
+			t.Errorf("Unexpected result. Got: %+v", dest)
+		}
+	})
+
+	t.Run("NilNormalize", func(t *testing.T) {
+		var dest NamingDTO
+		if err := CopyFrom(&dest, &src, WithNameMatcher(NewNameMatcher(nil))); !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("Expected ErrInvalidArgument, but got %v", err)
+		}
+		if err := CopyFromMap(&dest, map[string]interface{}{}, WithNameMatcher(&NameMatcher{})); !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("Expected ErrInvalidArgument, but got %v", err)
+		}
+	})
+
+	t.Run("AliasNotNormalized", func(t *testing.T) {
+		src := struct {
+			Id int `kopcup-alias:"userid"`
+		}{Id: 1}
+		var dest struct{ UserID int }
+		if err := CopyFrom(&dest, &src, WithNameMatcher(NameCaseInsensitive), WithStrict()); !errors.Is(err, ErrUnmatched) {
+			t.Errorf("Expected ErrUnmatched, but got %v", err)
+		}
+	})
+}
+
+func TestCopyFromNameMatcherAmbiguous(t *testing.T) {
+	t.Run("Destination", func(t *testing.T) {
+		src := struct{ UserId int }{UserId: 1}
+		var dest struct{ UserID, USERID int }
+		err := CopyFrom(&dest, &src, WithNameMatcher(NameCaseInsensitive))
+		if !errors.Is(err, ErrAmbiguousName) {
+			t.Fatalf("Expected ErrAmbiguousName, but got %v", err)
+		}
+		var fe *FieldError
+		if !errors.As(err, &fe) || fe.Path != "UserId" {
+			t.Errorf("Expected FieldError for UserId, but got %v", err)
+		}
+	})
+
+	t.Run("Source", func(t *testing.T) {
+		src := struct {
+			UserID  int
+			User_Id int
+		}{UserID: 1, User_Id: 2}
+		var dest struct{ UserId int }
+		if err := CopyFrom(&dest, &src, WithNameMatcher(NameSeparatorInsensitive)); !errors.Is(err, ErrAmbiguousName) {
+			t.Errorf("Expected ErrAmbiguousName, but got %v", err)
+		}
+	})
+
+	t.Run("SourceWithExact", func(t *testing.T) {
+		src := struct{ UserID, UserId int }{UserID: 1, UserId: 2}
+		var dest struct{ UserID int }
+		if err := CopyFrom(&dest, &src, WithNameMatcher(NameAcronymAware)); !errors.Is(err, ErrAmbiguousName) {
+			t.Errorf("Expected ErrAmbiguousName, but got %v", err)
+		}
+	})
+}
+
+func TestCopyFromMapNameMatcher(t *testing.T) {
+	var dest NamingDTO
+	src := map[string]interface{}{"user_id": 1, "user_name": "taro", "http_port": 8080}
+	if err := CopyFromMap(&dest, src, WithNameMatcher(NameAcronymAware), WithStrict()); err != nil {
+		t.Fatalf("CopyFromMap failed: %v", err)
+	}
+	expected := NamingDTO{UserID: 1, UserName: "taro", HttpPort: 8080}
+	if dest != expected {
+		t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", dest, expected)
+	}
+
+	src = map[string]interface{}{"user_id": 1, "userId": 2}
+	if err := CopyFromMap(&dest, src, WithNameMatcher(NameAcronymAware)); !errors.Is(err, ErrAmbiguousName) {
+		t.Errorf("Expected ErrAmbiguousName, but got %v", err)
+	}
+}
+
+func TestNameMatcherPlanCache(t *testing.T) {
+	countPlans := func() int {
+		n := 0
+		plans.Range(func(_, _ interface{}) bool {
+			n++
+			return true
+		})
+		return n
+	}
+
+	src := NamingModel{UserId: 1}
+	if err := CopyFrom(&NamingDTO{}, &src, WithNameMatcher(NewNameMatcher(strings.ToLower))); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	before := countPlans()
+	for i := 0; i < 10; i++ {
+		var dest NamingDTO
+		m := NewNameMatcher(strings.ToLower)
+		if err := CopyFrom(&dest, &src, WithNameMatcher(m)); err != nil {
+			t.Fatalf("CopyFrom failed: %v", err)
+		}
+		if dest.UserID != 1 {
+			t.Errorf("Unexpected result. Got: %+v", dest)
+		}
+	}
+	if after := countPlans(); after != before {
+		t.Errorf("Expected the shared plan cache not to grow, but got %d -> %d entries", before, after)
+	}
+
+	m := NameCaseInsensitive
+	p := planFor(reflect.TypeOf(NamingDTO{}), reflect.TypeOf(NamingModel{}), planOptions{names: m})
+	if p != planFor(reflect.TypeOf(NamingDTO{}), reflect.TypeOf(NamingModel{}), planOptions{names: m}) {
+		t.Error("Expected the cached plan to be reused for the same NameMatcher")
+	}
+}
@@ -0,0 +1,255 @@
+package kop2cup
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	tFmt "github.com/enecom-kaisa/kop-to-cup/time_format"
+)
+
+/*
+文字列から time.Time へ変換する場合に、オプション・タグ kopcup-tz のいずれも指定されていなければ使うタイムゾーンです。
+初期値は Asia/Tokyo（タイムゾーンデータベースがない環境では UTC+9 の固定タイムゾーン）です。
+*/
+var DefaultLocation = defaultLocation()
+
+func defaultLocation() *time.Location {
+	if loc, err := time.LoadLocation("Asia/Tokyo"); err == nil {
+		return loc
+	}
+	return time.FixedZone("JST", 9*60*60)
+}
+
+/*
+WithTagKeys を指定しない場合に項目の別名を探すタグのキーです（優先順）。
+いずれのタグにも名前がない項目は項目名で対応付けます。
+*/
+var DefaultTagKeys = []string{"kopcup-alias", "json", "db"}
+
+/*
+読み込み済みのタイムゾーンです。
+*/
+var locations sync.Map
+
+/*
+名前からタイムゾーンを読み込みます。同じ名前は初回のみ読み込みます。
+*/
+func loadLocation(name string) (*time.Location, error) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("convert error: invalid kopcup-tz %s: %w", name, err)
+	}
+	locations.Store(name, loc)
+	return loc, nil
+}
+
+/*
+CopyFrom 等の動作を指定します。
+With* 関数の戻り値のほか、既存の呼び出しとの互換のため次の値をそのまま指定できます（WithTimeFormat と同じ）。
+  - tFmt.TimeFormat
+  - 文字列（"2006/01/02" のような型のない定数。tFmt.StrToTimeFormat で解釈できない場合は ErrInvalidArgument）
+
+それ以外の値（nil を含む）を指定した場合は ErrInvalidArgument を返します。
+[]tFmt.TimeFormat を展開して渡す従来の呼び出し（CopyFrom(dest, src, fmts...)）は CopyFromFormat を使います。
+*/
+type Option interface{}
+
+type optionFunc func(*config)
+
+/*
+変換に失敗した場合の動作です。
+*/
+type ErrorMode int
+
+const (
+	FailFast   ErrorMode = iota // 最初に失敗した項目の *FieldError を返す
+	CollectAll                  // 全ての項目のコピーを試み、失敗した項目を FieldErrors として返す
+)
+
+/*
+1回のコピーで使う設定です。
+項目のタグで上書きする場合は複製して使います。
+  - tfmts タグで指定されていない場合の日付フォーマット（解析時は順に試行、書式化は先頭を使用。未指定は空）
+  - loc 文字列から time.Time へ変換する場合のタイムゾーン（nil の場合は DefaultLocation）
+  - epoch time.Time と数値の変換で使う Unix 時間の単位（未指定は 0）
+  - duration time.Duration と数値の変換で使う単位（未指定は 0）
+  - merge マージモード
+  - plan structPlan の作成に影響する設定
+  - strict コピー先のない項目をエラーにする
+  - errorMode 変換に失敗した場合の動作
+  - registry DefaultRegistry より優先する変換関数の登録先（nil 可）
+  - nilPolicy タグ kopcup-nil が指定されていない項目の nil ポインタの扱い
+  - customTagKeys WithTagKeys が指定された（CopyToMap のキーに plan.tagKeys を使う）
+*/
+type config struct {
+	tfmts     []tFmt.TimeFormat
+	loc       *time.Location
+	epoch     time.Duration
+	duration  time.Duration
+	merge     MergeMode
+	plan      planOptions
+	strict    bool
+	errorMode ErrorMode
+	registry  *Registry
+	nilPolicy NilPolicy
+
+	customTagKeys bool
+}
+
+/*
+タグで指定されていない場合の日付フォーマットを指定します。省略時は tFmt.RFC3339B です。
+*/
+func WithTimeFormat(tfmt tFmt.TimeFormat) Option {
+	return optionFunc(func(c *config) { c.tfmts = []tFmt.TimeFormat{tfmt} })
+}
+
+/*
+タグで指定されていない場合の日付フォーマットを複数指定します。
+文字列からの変換では順に試し、time.Time から文字列への変換では先頭を使います。
+*/
+func WithTimeFormats(tfmts ...tFmt.TimeFormat) Option {
+	return optionFunc(func(c *config) { c.tfmts = tfmts })
+}
+
+/*
+文字列から time.Time へ変換する場合のタイムゾーンを指定します。
+タグ kopcup-tz が指定された項目はタグが優先されます。省略時は DefaultLocation です。
+*/
+func WithLocation(loc *time.Location) Option {
+	return optionFunc(func(c *config) { c.loc = loc })
+}
+
+/*
+time.Time と数値の変換で使う Unix 時間の単位（time.Second、time.Millisecond、time.Microsecond、time.Nanosecond）を指定します。
+タグ kopcup-epoch が指定された項目はタグが優先されます。省略時は秒です。
+指定した場合、数字のみの文字列も Unix 時間として time.Time に変換し、time.Time は数字の文字列に変換します。
+*/
+func WithEpochUnit(unit time.Duration) Option {
+	return optionFunc(func(c *config) { c.epoch = unit })
+}
+
+/*
+time.Duration と整数・浮動小数点数の変換で使う単位（time.Hour 〜 time.Nanosecond）を指定します。
+タグ kopcup-duration が指定された項目はタグが優先されます。省略時は秒です。
+*/
+func WithDurationUnit(unit time.Duration) Option {
+	return optionFunc(func(c *config) { c.duration = unit })
+}
+
+/*
+コピー元の埋め込み構造体を展開せず、同名の1項目としてコピーします。
+項目ごとに指定する場合はタグ kopcup-embed:"keep" を使います。
+*/
+func WithKeepEmbedded() Option {
+	return optionFunc(func(c *config) { c.plan.keepEmbedded = true })
+}
+
+/*
+コピー元の項目に対応するコピー先の項目がない場合に ErrUnmatched を返します。
+CopyFromMap ではどの項目にも対応しないキーが対象です。
+*/
+func WithStrict() Option {
+	return optionFunc(func(c *config) { c.strict = true })
+}
+
+/*
+DefaultRegistry より優先する変換関数の登録先を指定します。
+*/
+func WithConverterRegistry(r *Registry) Option {
+	return optionFunc(func(c *config) { c.registry = r })
+}
+
+/*
+変換に失敗した場合の動作を指定します。省略時は FailFast です。
+*/
+func WithErrorMode(mode ErrorMode) Option {
+	return optionFunc(func(c *config) { c.errorMode = mode })
+}
+
+/*
+タグ kopcup-nil が指定されていない項目の nil ポインタの扱いを指定します。省略時は NilZero です。
+*/
+func WithNilPolicy(policy NilPolicy) Option {
+	return optionFunc(func(c *config) { c.nilPolicy = policy })
+}
+
+/*
+項目の別名を探すタグのキーを優先順に指定します。省略時は DefaultTagKeys です。
+  - 各タグの値の "," 以降（"omitempty" 等のオプション）は無視
+  - 名前が空または "-" のタグは次のタグを探し、いずれにもなければ項目名で対応付け
+  - 何も指定しない場合は項目名のみで対応付け
+*/
+func WithTagKeys(keys ...string) Option {
+	return optionFunc(func(c *config) {
+		c.plan.tagKeys = strings.Join(keys, " ")
+		c.customTagKeys = true
+	})
+}
+
+func newConfig(opts ...Option) (*config, error) {
+	c := &config{plan: planOptions{tagKeys: strings.Join(DefaultTagKeys, " ")}}
+	for _, opt := range opts {
+		switch o := opt.(type) {
+		case optionFunc:
+			o(c)
+		case tFmt.TimeFormat:
+			c.tfmts = []tFmt.TimeFormat{o}
+		case string:
+			tfmt, err := tFmt.StrToTimeFormat(o)
+			if err != nil {
+				return nil, fmt.Errorf("%w: time format %q: %v", ErrInvalidArgument, o, err)
+			}
+			c.tfmts = []tFmt.TimeFormat{tfmt}
+		default:
+			return nil, fmt.Errorf("%w: unsupported option %T", ErrInvalidArgument, opt)
+		}
+	}
+	if c.plan.names != nil && c.plan.names.normalize == nil {
+		return nil, fmt.Errorf("%w: NameMatcher without normalize func", ErrInvalidArgument)
+	}
+	return c, nil
+}
+
+/*
+タグで指定されていない場合の日付フォーマットを返します。未指定の場合は tFmt.RFC3339B のみです。
+*/
+func (c *config) timeFormats() []tFmt.TimeFormat {
+	if len(c.tfmts) == 0 {
+		return []tFmt.TimeFormat{tFmt.RFC3339B}
+	}
+	return c.tfmts
+}
+
+/*
+項目のタグで指定された設定で上書きした設定を返します。
+タグの指定がない場合は c をそのまま返します。
+kopcup-dateformat は tFmt.Register による登録・上書きを反映するため、呼び出しごとに解決します。
+*/
+func (c *config) override(tags tagOptions) (*config, error) {
+	if tags.dateformat == "" && tags.loc == nil && tags.epoch == 0 && tags.duration == 0 {
+		return c, nil
+	}
+	clone := *c
+	if tags.dateformat != "" {
+		tfmts, err := tFmt.StrToTimeFormats(tags.dateformat)
+		if err != nil {
+			return nil, err
+		}
+		clone.tfmts = tfmts
+	}
+	if tags.loc != nil {
+		clone.loc = tags.loc
+	}
+	if tags.epoch != 0 {
+		clone.epoch = tags.epoch
+	}
+	if tags.duration != 0 {
+		clone.duration = tags.duration
+	}
+	return &clone, nil
+}
@@ -0,0 +1,259 @@
+package kop2cup
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	tFmt "github.com/enecom-kaisa/kop-to-cup/time_format"
+)
+
+type SourceOptions struct {
+	Name    string
+	Date    string
+	Nick    *string
+	Unknown int
+}
+
+type DestinationOptions struct {
+	Name string
+	Date time.Time
+	Nick string
+}
+
+func TestOptions(t *testing.T) {
+	utc := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		Name     string
+		Src      SourceOptions
+		Options  []Option
+		Expected DestinationOptions
+		Err      error
+	}{
+		{
+			Name:     "LegacyTimeFormat",
+			Src:      SourceOptions{Name: "a", Date: "2024-04-01T18:00:00+09:00"},
+			Options:  []Option{tFmt.RFC3339},
+			Expected: DestinationOptions{Name: "a", Date: utc},
+		},
+		{
+			Name:     "LegacyUntypedLayout",
+			Src:      SourceOptions{Name: "a", Date: "2024/04/01 18:00"},
+			Options:  []Option{"2006/01/02 15:04"},
+			Expected: DestinationOptions{Name: "a", Date: utc},
+		},
+		{
+			Name:     "WithTimeFormat",
+			Src:      SourceOptions{Name: "a", Date: "2024-04-01 18:00:00"},
+			Options:  []Option{WithTimeFormat(tFmt.DateTime)},
+			Expected: DestinationOptions{Name: "a", Date: utc},
+		},
+		{
+			Name:     "WithLocation",
+			Src:      SourceOptions{Name: "a", Date: "2024-04-01 09:00:00"},
+			Options:  []Option{WithTimeFormat(tFmt.DateTime), WithLocation(time.UTC)},
+			Expected: DestinationOptions{Name: "a", Date: utc},
+		},
+		{
+			Name:    "WithNilPolicy",
+			Src:     SourceOptions{Name: "a", Date: "2024/04/01T18:00:00+09:00"},
+			Options: []Option{WithNilPolicy(NilError)},
+			Err:     ErrNilPointer,
+		},
+		{
+			Name:    "WithStrict",
+			Src:     SourceOptions{Name: "a", Nick: strPtr("b")},
+			Options: []Option{WithStrict()},
+			Err:     ErrUnmatched,
+		},
+		{
+			Name:    "InvalidOption",
+			Src:     SourceOptions{Name: "a"},
+			Options: []Option{42},
+			Err:     ErrInvalidArgument,
+		},
+		{
+			Name:    "NilOption",
+			Src:     SourceOptions{Name: "a"},
+			Options: []Option{nil},
+			Err:     ErrInvalidArgument,
+		},
+		{
+			Name:    "InvalidLayout",
+			Src:     SourceOptions{Name: "a"},
+			Options: []Option{"invalid"},
+			Err:     ErrInvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var dest DestinationOptions
+			err := CopyFrom(&dest, &tc.Src, tc.Options...)
+			if tc.Err != nil {
+				if !errors.Is(err, tc.Err) {
+					t.Fatalf("Expected %v, but got %v", tc.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if dest.Name != tc.Expected.Name || !dest.Date.Equal(tc.Expected.Date) || dest.Nick != tc.Expected.Nick {
+				t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", dest, tc.Expected)
+			}
+		})
+	}
+}
+
+func TestCopyFromFormat(t *testing.T) {
+	utc := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+	src := SourceOptions{Name: "a", Date: "2024/04/01 18:00"}
+
+	var dest DestinationOptions
+	if err := CopyFromFormat(&dest, &src, "2006/01/02 15:04"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !dest.Date.Equal(utc) {
+		t.Errorf("Unexpected Date. Got: %v, Expected: %v", dest.Date, utc)
+	}
+
+	fmts := []tFmt.TimeFormat{tFmt.RFC3339, "2006/01/02 15:04"}
+	dest = DestinationOptions{}
+	if err := CopyFromFormat(&dest, &src, fmts...); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !dest.Date.Equal(utc) {
+		t.Errorf("Unexpected Date. Got: %v, Expected: %v", dest.Date, utc)
+	}
+}
+
+func TestOptionsErrorMode(t *testing.T) {
+	src := SourceOptions{Name: "a", Date: "invalid"}
+	var dest DestinationOptions
+	err := CopyFrom(&dest, &src, WithStrict(), WithErrorMode(CollectAll))
+
+	var errs FieldErrors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("Expected 2 field errors, but got %v", err)
+	}
+	if errs[0].Path != "Unknown" || !errors.Is(errs[0], ErrUnmatched) {
+		t.Errorf("Unexpected first error: %v", errs[0])
+	}
+	if errs[1].Path != "Date" {
+		t.Errorf("Unexpected second error: %v", errs[1])
+	}
+	if dest.Name != "a" {
+		t.Errorf("Expected Name to be copied, but got %q", dest.Name)
+	}
+}
+
+func TestCopyFromMapStrict(t *testing.T) {
+	var dest DestinationOptions
+	err := CopyFromMap(&dest, map[string]interface{}{"Name": "a", "Extra": 1}, WithStrict())
+	var fe *FieldError
+	if !errors.As(err, &fe) || fe.Path != "Extra" || !errors.Is(err, ErrUnmatched) {
+		t.Fatalf("Expected ErrUnmatched for Extra, but got %v", err)
+	}
+
+	dest = DestinationOptions{}
+	err = CopyFromMap(&dest, map[string]interface{}{"Name": []int{1}, "Date": "x", "Nick": "b"}, WithErrorMode(CollectAll))
+	var errs FieldErrors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("Expected 2 field errors, but got %v", err)
+	}
+	if dest.Nick != "b" {
+		t.Errorf("Expected Nick to be copied, but got %q", dest.Nick)
+	}
+}
+
+type SourceLocation struct {
+	Local string `kopcup-dateformat:"2006-01-02 15:04:05"`
+	UTC   string `kopcup-dateformat:"2006-01-02 15:04:05" kopcup-tz:"UTC"`
+}
+
+type DestinationLocation struct {
+	Local time.Time
+	UTC   time.Time `kopcup-dateformat:"2006-01-02 15:04:05" kopcup-tz:"UTC"`
+}
+
+func TestLocation(t *testing.T) {
+	src := SourceLocation{Local: "2024-04-01 09:00:00", UTC: "2024-04-01 09:00:00"}
+	utc := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	testCases := []struct {
+		Name     string
+		Options  []Option
+		Expected time.Time
+	}{
+		{Name: "Default", Expected: time.Date(2024, 4, 1, 9, 0, 0, 0, DefaultLocation)},
+		{Name: "WithLocation", Options: []Option{WithLocation(ny)}, Expected: time.Date(2024, 4, 1, 9, 0, 0, 0, ny)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var dest DestinationLocation
+			if err := CopyFrom(&dest, &src, tc.Options...); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !dest.Local.Equal(tc.Expected) || dest.Local.Location() != tc.Expected.Location() {
+				t.Errorf("Unexpected Local. Got: %v, Expected: %v", dest.Local, tc.Expected)
+			}
+			if !dest.UTC.Equal(utc) || dest.UTC.Location() != time.UTC {
+				t.Errorf("Unexpected UTC. Got: %v, Expected: %v", dest.UTC, utc)
+			}
+		})
+	}
+
+	t.Run("DestinationTag", func(t *testing.T) {
+		var dest struct {
+			At time.Time `kopcup-tz:"UTC"`
+		}
+		src := struct {
+			At string `kopcup-dateformat:"2006-01-02 15:04:05"`
+		}{At: "2024-04-01 09:00:00"}
+		if err := CopyFrom(&dest, &src); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !dest.At.Equal(utc) || dest.At.Location() != time.UTC {
+			t.Errorf("Unexpected At. Got: %v, Expected: %v", dest.At, utc)
+		}
+	})
+
+	t.Run("CopyFromMap", func(t *testing.T) {
+		var dest DestinationLocation
+		if err := CopyFromMap(&dest, map[string]interface{}{"UTC": "2024-04-01 09:00:00"}, WithLocation(ny)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !dest.UTC.Equal(utc) {
+			t.Errorf("Unexpected UTC. Got: %v, Expected: %v", dest.UTC, utc)
+		}
+	})
+
+	t.Run("DefaultLocation", func(t *testing.T) {
+		defer func(loc *time.Location) { DefaultLocation = loc }(DefaultLocation)
+		DefaultLocation = time.UTC
+		var dest DestinationLocation
+		if err := CopyFrom(&dest, &src); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !dest.Local.Equal(utc) {
+			t.Errorf("Unexpected Local. Got: %v, Expected: %v", dest.Local, utc)
+		}
+	})
+}
+
+func TestLocationInvalidTag(t *testing.T) {
+	src := struct {
+		Date string `kopcup-tz:"Nowhere/Invalid"`
+	}{Date: "2024/04/01T09:00:00+09:00"}
+	var dest struct{ Date time.Time }
+	var fe *FieldError
+	if err := CopyFrom(&dest, &src); !errors.As(err, &fe) || fe.Path != "Date" {
+		t.Errorf("Expected a FieldError for Date, but got %v", err)
+	}
+}
@@ -0,0 +1,536 @@
+package kop2cup
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+コピー元の1項目をコピー先のどの項目へどうコピーするかを表します。
+  - name コピー元の項目名（エラーのパスに使用）
+  - srcIndex コピー元の項目の添字（埋め込み構造体を経由する場合は複数）
+  - destIndex コピー先の項目の添字（埋め込み構造体を経由する場合は複数）
+  - destType コピー先の項目の型
+  - tags タグ kopcup-dateformat 等で指定された設定（コピー元の項目、コピー元で未指定のタグはコピー先の項目のタグ）
+  - nilPolicy タグ kopcup-nil の値（指定がなければ nil）
+  - omitEmpty タグ kopcup:"omitempty"（コピー元・コピー先のいずれか）が指定されている
+  - err タグの解析エラー（コピー時に報告）
+*/
+type fieldPlan struct {
+	name      string
+	srcIndex  []int
+	destIndex []int
+	destType  reflect.Type
+	tags      tagOptions
+	nilPolicy *NilPolicy
+	omitEmpty bool
+	err       error
+}
+
+/*
+コピー元とコピー先の型の組み合わせに対するコピー手順です。
+  - fields コピーする項目
+  - skipped コピーしない項目（name、srcIndex と strict の場合に報告する err のみ）
+    コピー先のない公開項目（ErrUnmatched）、コピー元・コピー先のいずれかが非公開の項目（ErrUnexported）、
+    同じ深さの複数の埋め込み構造体にあり曖昧な公開項目（ErrAmbiguousName）
+*/
+type structPlan struct {
+	fields  []fieldPlan
+	skipped []fieldPlan
+}
+
+/*
+structPlan の作成に影響する設定です。キャッシュのキーに含めます。
+  - keepEmbedded コピー元の埋め込み構造体を展開せず1項目として扱う
+  - names 項目名の対応付けに使う NameMatcher（nil の場合は同名のみ）
+  - tagKeys 別名を探すタグのキー（優先順に空白区切り）
+*/
+type planOptions struct {
+	keepEmbedded bool
+	names        *NameMatcher
+	tagKeys      string
+}
+
+/*
+別名を探すタグのキーを優先順に返します。
+*/
+func (o planOptions) aliasKeys() []string {
+	return strings.Fields(o.tagKeys)
+}
+
+type planKey struct {
+	src  reflect.Type
+	dest reflect.Type
+	opts planOptions
+}
+
+/*
+型の組み合わせごとに作成済みの structPlan です（NameMatcher を指定した場合を除く）。
+*/
+var plans sync.Map
+
+/*
+destType と srcType の組み合わせに対する structPlan を返します。
+初回のみ作成し、以降はキャッシュしたものを返します。
+NameMatcher を指定した場合は NameMatcher ごとにキャッシュするため、呼び出しごとに作成した NameMatcher のキャッシュは残りません。
+*/
+func planFor(destType reflect.Type, srcType reflect.Type, opts planOptions) *structPlan {
+	cache := &plans
+	key := planKey{src: srcType, dest: destType, opts: opts}
+	if opts.names != nil {
+		cache = &opts.names.plans
+		key.opts.names = nil
+	}
+	if p, ok := cache.Load(key); ok {
+		return p.(*structPlan)
+	}
+	p, _ := cache.LoadOrStore(key, buildPlan(destType, srcType, opts))
+	return p.(*structPlan)
+}
+
+/*
+コピー元の各項目について、aliasMatcher でコピー先の項目を探して structPlan を作成します。
+コピー元・コピー先のいずれかが非公開の項目は読み書きできないためコピーしません。
+コピー元・コピー先のいずれかにタグ kopcup:"-" がある項目はコピーせず、strict の場合も報告しません。
+コピー元の埋め込み構造体の項目は srcFields で展開します。
+複数のコピー元の項目が同じコピー先の項目に対応する場合、後の項目は ErrAliasConflict とします。
+*/
+func buildPlan(destType reflect.Type, srcType reflect.Type, opts planOptions) *structPlan {
+	p := &structPlan{}
+	fields, ambiguous := srcFields(destType, srcType, opts)
+	for _, field := range ambiguous {
+		if field.IsExported() {
+			p.skipped = append(p.skipped, fieldPlan{name: field.Name, srcIndex: field.Index, err: ErrAmbiguousName})
+		}
+	}
+	m := newAliasMatcher(destType, fields, opts)
+	claimed := map[string]string{}
+	add := func(name string, field reflect.StructField, destField reflect.StructField) {
+		if tag, _ := parseKopcupTag(destField.Tag.Get("kopcup")); !tag.ignore {
+			key := fmt.Sprint(destField.Index)
+			if other, ok := claimed[key]; ok {
+				err := fmt.Errorf("%w: %s and %s both map to %s", ErrAliasConflict, other, name, destField.Name)
+				p.fields = append(p.fields, fieldPlan{name: name, srcIndex: field.Index, err: err})
+				return
+			}
+			claimed[key] = name
+		}
+		p.add(name, field, destField)
+	}
+	for _, field := range fields {
+		destField, ok, err := m.match(field)
+		if err != nil {
+			p.fields = append(p.fields, fieldPlan{name: field.Name, srcIndex: field.Index, err: err})
+			continue
+		}
+		if !ok {
+			if field.IsExported() && !m.pathRoots[field.Name] {
+				p.skipped = append(p.skipped, fieldPlan{name: field.Name, srcIndex: field.Index, err: ErrUnmatched})
+			}
+			continue
+		}
+		add(field.Name, field, destField)
+	}
+
+	// コピー先で別名にパスを指定した項目は、コピー元のネストした項目からコピー
+	for _, destField := range m.destPaths {
+		alias := m.alias(destField)
+		if field, ok := fieldByPath(srcType, alias); ok {
+			if tag, _ := parseKopcupTag(field.Tag.Get("kopcup")); !tag.ignore {
+				add(alias, field, destField)
+			}
+		}
+	}
+	return p
+}
+
+/*
+コピー元の項目 field をコピー先の項目 destField へコピーする fieldPlan を追加します。
+  - name エラーのパスに使う名前
+  - コピー先にタグ kopcup:"-" がある場合は追加しない
+  - 非公開の項目は skipped に追加
+*/
+func (p *structPlan) add(name string, field reflect.StructField, destField reflect.StructField) {
+	destTag, destErr := parseKopcupTag(destField.Tag.Get("kopcup"))
+	if destTag.ignore {
+		return
+	}
+	if !field.IsExported() || !destField.IsExported() {
+		p.skipped = append(p.skipped, fieldPlan{name: name, srcIndex: field.Index, err: ErrUnexported})
+		return
+	}
+
+	f := fieldPlan{name: name, srcIndex: field.Index, destIndex: destField.Index, destType: destField.Type}
+	f.tags, f.err = fieldTags(field)
+	if destTags, err := fieldTags(destField); err != nil {
+		if f.err == nil {
+			f.err = err
+		}
+	} else {
+		f.tags = f.tags.orElse(destTags)
+	}
+	srcTag, srcErr := parseKopcupTag(field.Tag.Get("kopcup"))
+	f.omitEmpty = srcTag.omitEmpty || destTag.omitEmpty
+	if f.err == nil {
+		f.err = errors.Join(srcErr, destErr)
+	}
+	if tag := field.Tag.Get("kopcup-nil"); tag != "" {
+		if policy, err := strToNilPolicy(tag); err != nil && f.err == nil {
+			f.err = err
+		} else {
+			f.nilPolicy = &policy
+		}
+	}
+	p.fields = append(p.fields, f)
+}
+
+/*
+"Address.City" のように "." で区切ったパスの項目を返します。Index は t からの添字です。
+途中の構造体はポインタでも可。途中に非公開の項目がある場合や、項目がない場合は ok は false です。
+*/
+func fieldByPath(t reflect.Type, path string) (reflect.StructField, bool) {
+	var field reflect.StructField
+	var index []int
+	for _, name := range strings.Split(path, ".") {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return reflect.StructField{}, false
+		}
+		f, ok := t.FieldByName(name)
+		if !ok || !f.IsExported() {
+			return reflect.StructField{}, false
+		}
+		index = append(index, f.Index...)
+		field, t = f, f.Type
+	}
+	field.Index = index
+	return field, true
+}
+
+/*
+項目の別名を keys のタグから順に探して返します。別名がなければ空文字列です。
+  - タグの値の "," 以降（"omitempty" 等のオプション）は除く
+  - 名前が空（json:",omitempty" 等）または "-" のタグは無視して次のタグを探す
+*/
+func fieldAlias(field reflect.StructField, keys []string) string {
+	for _, key := range keys {
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return ""
+}
+
+/*
+コピー元の項目とコピー先の項目を別名（両方の構造体）と項目名で対応付けます。
+別名は tagKeys のタグ（省略時は kopcup-alias、json、db の順）から fieldAlias で探します。
+  - tagKeys 別名を探すタグのキー
+  - destAliases コピー先の別名ごとの項目（パスを除く）
+  - destPaths 別名にパス（"Address.City"）を指定したコピー先の項目
+  - pathRoots destPaths のパスの先頭の名前（コピー元の項目として使用済み）
+  - srcNames コピー元の項目名
+  - names 項目名の対応付けに使う NameMatcher（nil の場合は同名のみ）
+  - destNames 正規化した名前ごとのコピー先の項目（names が nil の場合は nil）
+  - srcNormalized 正規化した名前ごとのコピー元の項目数（names が nil の場合は nil）
+*/
+type aliasMatcher struct {
+	tagKeys       []string
+	destType      reflect.Type
+	destAliases   map[string][]reflect.StructField
+	destPaths     []reflect.StructField
+	pathRoots     map[string]bool
+	srcNames      map[string]bool
+	names         *NameMatcher
+	destNames     map[string][]reflect.StructField
+	srcNormalized map[string]int
+}
+
+func newAliasMatcher(destType reflect.Type, srcFields []reflect.StructField, opts planOptions) *aliasMatcher {
+	names := opts.names
+	m := &aliasMatcher{tagKeys: opts.aliasKeys(), destType: destType, destAliases: map[string][]reflect.StructField{}, pathRoots: map[string]bool{}, srcNames: map[string]bool{}, names: names}
+	for _, field := range reflect.VisibleFields(destType) {
+		alias := m.alias(field)
+		if root, _, ok := strings.Cut(alias, "."); ok {
+			m.destPaths = append(m.destPaths, field)
+			m.pathRoots[root] = true
+		} else if alias != "" {
+			m.destAliases[alias] = append(m.destAliases[alias], field)
+		}
+	}
+	for _, field := range srcFields {
+		m.srcNames[field.Name] = true
+	}
+
+	if names != nil {
+		m.destNames = map[string][]reflect.StructField{}
+		for _, field := range reflect.VisibleFields(destType) {
+			// 浅い項目に隠された項目、同じ深さに同名の項目があり曖昧な項目は除外
+			if f, ok := destType.FieldByName(field.Name); ok && reflect.DeepEqual(f.Index, field.Index) {
+				key := names.normalize(field.Name)
+				m.destNames[key] = append(m.destNames[key], field)
+			}
+		}
+		m.srcNormalized = map[string]int{}
+		for _, field := range srcFields {
+			m.srcNormalized[names.normalize(field.Name)]++
+		}
+	}
+	return m
+}
+
+func (m *aliasMatcher) alias(field reflect.StructField) string {
+	return fieldAlias(field, m.tagKeys)
+}
+
+/*
+コピー元の項目に対応するコピー先の項目を次の順に探します。
+ 1. コピー元の別名と同名のコピー先の項目（パスの場合はネストした項目）
+ 2. 別名がコピー元の項目名、またはコピー元と同じ別名のコピー先の項目
+ 3. 同名のコピー先の項目（別名で別のコピー元の項目を指定しているものを除く）
+ 4. NameMatcher が指定されている場合、正規化した名前が同じコピー先の項目（3 と同じ除外）
+
+次の場合は ErrAliasConflict を返します。
+  - 2 に該当するコピー先の項目が複数ある
+  - 1 と 2 が異なるコピー先の項目を指す
+  - 1 のコピー先の項目が別名で別のコピー元の項目を指定している
+
+4 に該当するコピー先の項目が複数ある場合、または他のコピー元の項目も同じ名前に正規化される場合は ErrAmbiguousName を返します。
+*/
+func (m *aliasMatcher) match(field reflect.StructField) (reflect.StructField, bool, error) {
+	alias := m.alias(field)
+	byDest := m.destAliases[field.Name]
+	if alias != "" && alias != field.Name {
+		byDest = append(append([]reflect.StructField(nil), byDest...), m.destAliases[alias]...)
+	}
+	if len(byDest) > 1 {
+		return reflect.StructField{}, false, fmt.Errorf("%w: %s is claimed by destination fields %s and %s", ErrAliasConflict, field.Name, byDest[0].Name, byDest[1].Name)
+	}
+
+	if strings.Contains(alias, ".") {
+		if destField, ok := fieldByPath(m.destType, alias); ok {
+			return destField, true, nil
+		}
+	} else if alias != "" {
+		if destField, ok := m.destType.FieldByName(alias); ok {
+			if len(byDest) == 1 && byDest[0].Name != destField.Name {
+				return reflect.StructField{}, false, fmt.Errorf("%w: %s maps to %s by source alias but to %s by destination alias", ErrAliasConflict, field.Name, destField.Name, byDest[0].Name)
+			}
+			if claimed := m.claimedBy(destField); claimed != "" && claimed != field.Name && claimed != alias {
+				return reflect.StructField{}, false, fmt.Errorf("%w: %s maps to %s which declares alias %s", ErrAliasConflict, field.Name, destField.Name, claimed)
+			}
+			return destField, true, nil
+		}
+	}
+	if len(byDest) == 1 {
+		return byDest[0], true, nil
+	}
+	destField, ok := m.destType.FieldByName(field.Name)
+	if !ok && m.names != nil {
+		return m.matchNormalized(field)
+	}
+	if ok {
+		if claimed := m.claimedBy(destField); claimed != "" && claimed != field.Name {
+			return reflect.StructField{}, false, nil
+		}
+	}
+	return destField, ok, nil
+}
+
+/*
+正規化した名前が同じコピー先の項目を探します。
+*/
+func (m *aliasMatcher) matchNormalized(field reflect.StructField) (reflect.StructField, bool, error) {
+	key := m.names.normalize(field.Name)
+	candidates := m.destNames[key]
+	switch {
+	case len(candidates) == 0:
+		return reflect.StructField{}, false, nil
+	case len(candidates) > 1:
+		return reflect.StructField{}, false, fmt.Errorf("%w: %s matches destination fields %s and %s", ErrAmbiguousName, field.Name, candidates[0].Name, candidates[1].Name)
+	case m.srcNormalized[key] > 1:
+		return reflect.StructField{}, false, fmt.Errorf("%w: %s and other source fields match destination field %s", ErrAmbiguousName, field.Name, candidates[0].Name)
+	}
+	if claimed := m.claimedBy(candidates[0]); claimed != "" && claimed != field.Name {
+		return reflect.StructField{}, false, nil
+	}
+	return candidates[0], true, nil
+}
+
+/*
+コピー先の項目の別名がコピー元の項目名であれば、その名前を返します。
+*/
+func (m *aliasMatcher) claimedBy(destField reflect.StructField) string {
+	if alias := m.alias(destField); m.srcNames[alias] {
+		return alias
+	}
+	return ""
+}
+
+/*
+コピー元の項目を定義順に返します。Index は srcType からの添字です。
+  - タグ kopcup:"-" の項目は除外
+  - 埋め込み構造体（ポインタ・非公開の型を含む）は、その項目を昇格した項目として展開
+  - 同名の項目は Go の規則に従い、浅い項目を優先し、同じ深さに複数あれば曖昧として除外（ambiguous に名前ごとに1つ返す）
+  - keepEmbedded（WithKeepEmbedded）、タグ kopcup-embed:"keep"、またはコピー先に同名の項目がある場合は展開せず1項目として扱う
+*/
+func srcFields(destType reflect.Type, srcType reflect.Type, opts planOptions) (fields []reflect.StructField, ambiguous []reflect.StructField) {
+	type candidate struct {
+		field reflect.StructField
+		depth int
+	}
+	var candidates []candidate
+	visited := map[reflect.Type]bool{}
+
+	var walk func(t reflect.Type, index []int, depth int)
+	walk = func(t reflect.Type, index []int, depth int) {
+		visited[t] = true
+		defer delete(visited, t)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			field.Index = append(append([]int(nil), index...), i)
+			if tag, _ := parseKopcupTag(field.Tag.Get("kopcup")); tag.ignore {
+				continue
+			}
+
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if field.Anonymous && embedded.Kind() == reflect.Struct && !visited[embedded] &&
+				!opts.keepEmbedded && field.Tag.Get("kopcup-embed") != "keep" {
+				if _, ok := destType.FieldByName(field.Name); !ok {
+					walk(embedded, field.Index, depth+1)
+					continue
+				}
+			}
+			candidates = append(candidates, candidate{field: field, depth: depth})
+		}
+	}
+	walk(srcType, nil, 0)
+
+	shallowest := map[string]int{}
+	count := map[string]int{}
+	for _, c := range candidates {
+		if d, ok := shallowest[c.field.Name]; !ok || c.depth < d {
+			shallowest[c.field.Name] = c.depth
+			count[c.field.Name] = 0
+		}
+		if c.depth == shallowest[c.field.Name] {
+			count[c.field.Name]++
+		}
+	}
+	reported := map[string]bool{}
+	for _, c := range candidates {
+		if c.depth != shallowest[c.field.Name] {
+			continue
+		}
+		if count[c.field.Name] == 1 {
+			fields = append(fields, c.field)
+		} else if !reported[c.field.Name] {
+			reported[c.field.Name] = true
+			ambiguous = append(ambiguous, c.field)
+		}
+	}
+	return fields, ambiguous
+}
+
+/*
+タグ kopcup の値です。
+  - ignore "-"（コピーしない）
+  - omitEmpty "omitempty"（コピー元がゼロ値の場合はコピーしない）
+*/
+type kopcupTag struct {
+	ignore    bool
+	omitEmpty bool
+}
+
+/*
+タグ kopcup の値を解析します。"omitempty" 等のオプションは "," で区切って指定します。
+*/
+func parseKopcupTag(tag string) (kopcupTag, error) {
+	if tag == "-" {
+		return kopcupTag{ignore: true}, nil
+	}
+	var t kopcupTag
+	for _, opt := range strings.Split(tag, ",") {
+		switch opt {
+		case "":
+		case "omitempty":
+			t.omitEmpty = true
+		default:
+			return t, errors.New("convert error: invalid kopcup " + tag)
+		}
+	}
+	return t, nil
+}
+
+/*
+項目のタグで指定された設定です。指定がなければゼロ値です。
+  - dateformat タグ kopcup-dateformat の値（"|" 区切りで複数可。tFmt.Register で登録した名前を使えるようにコピー時に解決）
+  - loc タグ kopcup-tz のタイムゾーン
+  - epoch タグ kopcup-epoch の Unix 時間の単位
+  - duration タグ kopcup-duration の time.Duration と数値の変換の単位
+*/
+type tagOptions struct {
+	dateformat string
+	loc        *time.Location
+	epoch      time.Duration
+	duration   time.Duration
+}
+
+/*
+t で指定されていない kopcup-dateformat、kopcup-tz、kopcup-epoch、kopcup-duration を other（コピー先の項目のタグ）で補います。
+コピー元・コピー先の両方で指定されている場合はコピー元が優先されます（タグごとに判定）。
+*/
+func (t tagOptions) orElse(other tagOptions) tagOptions {
+	if t.dateformat == "" {
+		t.dateformat = other.dateformat
+	}
+	if t.loc == nil {
+		t.loc = other.loc
+	}
+	if t.epoch == 0 {
+		t.epoch = other.epoch
+	}
+	if t.duration == 0 {
+		t.duration = other.duration
+	}
+	return t
+}
+
+/*
+項目のタグ kopcup-dateformat、kopcup-tz、kopcup-epoch、kopcup-duration を解析します。
+kopcup-dateformat は値のみを保持し、config.override で解決します。
+*/
+func fieldTags(field reflect.StructField) (tagOptions, error) {
+	tags := tagOptions{dateformat: field.Tag.Get("kopcup-dateformat")}
+	if tz := field.Tag.Get("kopcup-tz"); tz != "" {
+		l, err := loadLocation(tz)
+		if err != nil {
+			return tagOptions{}, err
+		}
+		tags.loc = l
+	}
+	if unit := field.Tag.Get("kopcup-epoch"); unit != "" {
+		u, err := strToEpochUnit(unit)
+		if err != nil {
+			return tagOptions{}, err
+		}
+		tags.epoch = u
+	}
+	if unit := field.Tag.Get("kopcup-duration"); unit != "" {
+		u, err := strToDurationUnit(unit)
+		if err != nil {
+			return tagOptions{}, err
+		}
+		tags.duration = u
+	}
+	return tags, nil
+}
@@ -0,0 +1,592 @@
+package kop2cup
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	tFmt "github.com/enecom-kaisa/kop-to-cup/time_format"
+)
+
+func TestPlanFor(t *testing.T) {
+	destType := reflect.TypeOf(DestinationStruct2{})
+	srcType := reflect.TypeOf(SourceStruct2{})
+
+	c, err := newConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := planFor(destType, srcType, c.plan)
+	if p != planFor(destType, srcType, c.plan) {
+		t.Error("Expected the cached plan to be reused")
+	}
+
+	type expectedField struct {
+		Name      string
+		DestIndex []int
+		TimeFmt   []tFmt.TimeFormat
+	}
+	rfc3339 := []tFmt.TimeFormat{tFmt.RFC3339}
+	expected := []expectedField{
+		{Name: "FieldString", DestIndex: []int{0}, TimeFmt: rfc3339},
+		{Name: "FieldInt", DestIndex: []int{1}},
+		{Name: "FieldBool", DestIndex: []int{2}},
+		{Name: "FieldTime", DestIndex: []int{3}, TimeFmt: rfc3339},
+		{Name: "Float", DestIndex: []int{4}},
+	}
+	result := make([]expectedField, len(p.fields))
+	for i, f := range p.fields {
+		fc, err := c.override(f.tags)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var tfmts []tFmt.TimeFormat
+		if f.tags.dateformat != "" {
+			tfmts = fc.tfmts
+		}
+		result[i] = expectedField{Name: f.name, DestIndex: f.destIndex, TimeFmt: tfmts}
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Unexpected plan. \n      Got: %+v\n Expected: %+v", result, expected)
+	}
+}
+
+func TestPlanForUnmatched(t *testing.T) {
+	p := planFor(reflect.TypeOf(struct{ A int }{}), reflect.TypeOf(struct{ B int }{}), planOptions{})
+	if len(p.fields) != 0 {
+		t.Errorf("Unexpected plan. Got: %+v, Expected no fields", p.fields)
+	}
+}
+
+func TestCopyFromConcurrent(t *testing.T) {
+	src := newBenchmarkSource()
+	var wg sync.WaitGroup
+	errs := make([]error, 16)
+	dests := make([]DestinationStruct, 16)
+	for i := range dests {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = CopyFrom(&dests[i], &src)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, dest := range dests {
+		if errs[i] != nil {
+			t.Fatalf("CopyFrom failed: %v", errs[i])
+		}
+		if dest.DestStringField6 != "" || dest.DestIntField5 != src.IntField5 || !dest.TimeField6.Equal(src.TimeField6) {
+			t.Errorf("Unexpected result. Got: %+v", dest)
+		}
+	}
+}
+
+type BaseModel struct {
+	ID        int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Audit struct {
+	ID      string
+	Comment string
+}
+
+type Owner struct {
+	Name string
+}
+
+type SourceEmbedded struct {
+	BaseModel
+	*Owner
+	Name string
+}
+
+type SourceAmbiguous struct {
+	BaseModel
+	Audit
+	Comment string
+}
+
+type DestinationFlat struct {
+	ID        int
+	CreatedAt time.Time
+	Comment   string
+	Name      string
+}
+
+type DestinationEmbedded struct {
+	BaseModel
+	Name string
+}
+
+type DestinationEmbeddedPointer struct {
+	*BaseModel
+	Name string
+}
+
+func TestCopyFromEmbedded(t *testing.T) {
+	created := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	base := BaseModel{ID: 7, CreatedAt: created, UpdatedAt: created}
+
+	t.Run("Flatten", func(t *testing.T) {
+		var dest DestinationFlat
+		if err := CopyFrom(&dest, &SourceEmbedded{BaseModel: base, Owner: &Owner{Name: "owner"}, Name: "item"}); err != nil {
+			t.Fatalf("CopyFrom failed: %v", err)
+		}
+		expected := DestinationFlat{ID: 7, CreatedAt: created, Name: "item"}
+		if !reflect.DeepEqual(dest, expected) {
+			t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", dest, expected)
+		}
+	})
+
+	t.Run("Ambiguous", func(t *testing.T) {
+		var dest DestinationFlat
+		if err := CopyFrom(&dest, &SourceAmbiguous{BaseModel: base, Audit: Audit{ID: "x", Comment: "audit"}, Comment: "outer"}); err != nil {
+			t.Fatalf("CopyFrom failed: %v", err)
+		}
+		expected := DestinationFlat{CreatedAt: created, Comment: "outer"}
+		if !reflect.DeepEqual(dest, expected) {
+			t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", dest, expected)
+		}
+	})
+
+	t.Run("AmbiguousStrict", func(t *testing.T) {
+		var dest DestinationFlat
+		src := SourceAmbiguous{BaseModel: base, Audit: Audit{ID: "x", Comment: "audit"}, Comment: "outer"}
+		err := CopyFrom(&dest, &src, WithStrict(), WithErrorMode(CollectAll))
+		var errs FieldErrors
+		if !errors.As(err, &errs) {
+			t.Fatalf("Expected FieldErrors, but got %v", err)
+		}
+		found := false
+		for _, fe := range errs {
+			if fe.Path == "ID" && errors.Is(fe, ErrAmbiguousName) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected ErrAmbiguousName for ID, but got %v", err)
+		}
+	})
+
+	t.Run("NilEmbeddedPointer", func(t *testing.T) {
+		dest := DestinationFlat{Name: "keep"}
+		if err := CopyFrom(&dest, &struct{ *Owner }{}); err != nil {
+			t.Fatalf("CopyFrom failed: %v", err)
+		}
+		if dest.Name != "keep" {
+			t.Errorf("Expected Name to be unchanged, but got %q", dest.Name)
+		}
+	})
+
+	t.Run("SameEmbed", func(t *testing.T) {
+		var dest DestinationEmbedded
+		if err := CopyFrom(&dest, &SourceEmbedded{BaseModel: base, Name: "item"}); err != nil {
+			t.Fatalf("CopyFrom failed: %v", err)
+		}
+		if dest.BaseModel != base || dest.Name != "item" {
+			t.Errorf("Unexpected result. Got: %+v", dest)
+		}
+	})
+
+	t.Run("DestinationPointer", func(t *testing.T) {
+		var dest DestinationEmbeddedPointer
+		if err := CopyFrom(&dest, &DestinationFlat{ID: 3, CreatedAt: created}); err != nil {
+			t.Fatalf("CopyFrom failed: %v", err)
+		}
+		if dest.BaseModel == nil || dest.ID != 3 || !dest.CreatedAt.Equal(created) {
+			t.Errorf("Unexpected result. Got: %+v", dest.BaseModel)
+		}
+	})
+
+	t.Run("KeepEmbedded", func(t *testing.T) {
+		var dest DestinationFlat
+		err := CopyFrom(&dest, &SourceEmbedded{BaseModel: base, Name: "item"}, WithKeepEmbedded(), WithStrict())
+		if !errors.Is(err, ErrUnmatched) {
+			t.Fatalf("Expected ErrUnmatched, but got %v", err)
+		}
+		if dest.ID != 0 {
+			t.Errorf("Expected ID not to be copied, but got %d", dest.ID)
+		}
+	})
+
+	t.Run("KeepTag", func(t *testing.T) {
+		var dest DestinationFlat
+		src := struct {
+			BaseModel `kopcup-embed:"keep"`
+			Name      string
+		}{BaseModel: base, Name: "item"}
+		if err := CopyFrom(&dest, &src); err != nil {
+			t.Fatalf("CopyFrom failed: %v", err)
+		}
+		if dest.ID != 0 || dest.Name != "item" {
+			t.Errorf("Unexpected result. Got: %+v", dest)
+		}
+	})
+}
+
+type base struct {
+	ID int
+}
+
+type SourceUnexported struct {
+	base
+	Name   string
+	secret string
+	Note   string
+}
+
+type DestinationUnexported struct {
+	ID     int
+	name   string
+	secret string
+	Note   string
+}
+
+type DestinationUnexportedEmbed struct {
+	*base
+	Note string
+}
+
+func TestCopyFromUnexported(t *testing.T) {
+	src := SourceUnexported{base: base{ID: 5}, Name: "name", secret: "secret", Note: "note"}
+
+	var dest DestinationUnexported
+	if err := CopyFrom(&dest, &src); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if expected := (DestinationUnexported{ID: 5, Note: "note"}); dest != expected {
+		t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", dest, expected)
+	}
+
+	err := CopyFrom(&DestinationUnexported{}, &src, WithStrict(), WithErrorMode(CollectAll))
+	var errs FieldErrors
+	if !errors.As(err, &errs) || len(errs) != 2 || !errors.Is(errs[0], ErrUnmatched) || errs[1].Path != "secret" || !errors.Is(errs[1], ErrUnexported) {
+		t.Errorf("Expected ErrUnmatched for Name and ErrUnexported for secret, but got %v", err)
+	}
+
+	err = CopyFrom(&DestinationUnexported{}, &struct {
+		Name string `kopcup-alias:"name"`
+	}{Name: "name"}, WithStrict())
+	if !errors.Is(err, ErrUnexported) {
+		t.Errorf("Expected ErrUnexported for name, but got %v", err)
+	}
+
+	var embed DestinationUnexportedEmbed
+	if err := CopyFrom(&embed, &src); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if embed.base != nil || embed.Note != "note" {
+		t.Errorf("Unexpected result. Got: %+v", embed)
+	}
+	if err := CopyFrom(&embed, &src, WithStrict()); !errors.Is(err, ErrUnexported) {
+		t.Errorf("Expected ErrUnexported for ID, but got %v", err)
+	}
+}
+
+type SourceKopcupTag struct {
+	ID       int    `kopcup:"-"`
+	Password string `kopcup:"-"`
+	Name     string `kopcup:"omitempty"`
+	Age      int    `kopcup:",omitempty"`
+	Email    string
+	Memo     string
+}
+
+type DestinationKopcupTag struct {
+	ID       int
+	Password string
+	Name     string
+	Age      int
+	Email    string `kopcup:"omitempty"`
+	Memo     string `kopcup:"-"`
+}
+
+func TestCopyFromKopcupTag(t *testing.T) {
+	dest := DestinationKopcupTag{ID: 1, Name: "keep", Age: 30, Email: "keep@example.com", Memo: "keep"}
+	src := SourceKopcupTag{ID: 2, Password: "secret", Memo: "memo"}
+	if err := CopyFrom(&dest, &src, WithStrict()); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	expected := DestinationKopcupTag{ID: 1, Name: "keep", Age: 30, Email: "keep@example.com", Memo: "keep"}
+	if dest != expected {
+		t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", dest, expected)
+	}
+
+	src = SourceKopcupTag{Name: "name", Age: 20, Email: "new@example.com"}
+	if err := CopyFrom(&dest, &src); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	expected = DestinationKopcupTag{ID: 1, Name: "name", Age: 20, Email: "new@example.com", Memo: "keep"}
+	if dest != expected {
+		t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", dest, expected)
+	}
+
+	invalid := struct {
+		Name string `kopcup:"omitzero"`
+	}{Name: "name"}
+	if err := CopyFrom(&dest, &invalid); err == nil {
+		t.Error("Expected an error for invalid kopcup tag, but got none.")
+	}
+}
+
+type UserEntity struct {
+	UserID   int    `kopcup-alias:"ID"`
+	FullName string `kopcup-alias:"Name"`
+	Mail     string
+}
+
+type UserDTO struct {
+	ID    int
+	Name  string
+	Email string `kopcup-alias:"Mail"`
+}
+
+func TestCopyFromDestinationAlias(t *testing.T) {
+	entity := UserEntity{UserID: 1, FullName: "taro", Mail: "taro@example.com"}
+
+	var dto UserDTO
+	if err := CopyFrom(&dto, &entity); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if expected := (UserDTO{ID: 1, Name: "taro", Email: "taro@example.com"}); dto != expected {
+		t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", dto, expected)
+	}
+
+	var back UserEntity
+	if err := CopyFrom(&back, &dto, WithStrict()); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if back != entity {
+		t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", back, entity)
+	}
+
+	t.Run("ClaimedByAlias", func(t *testing.T) {
+		src := struct {
+			ID   int
+			Code int
+		}{ID: 1, Code: 2}
+		var dest struct {
+			ID int `kopcup-alias:"Code"`
+		}
+		if err := CopyFrom(&dest, &src); err != nil {
+			t.Fatalf("CopyFrom failed: %v", err)
+		}
+		if dest.ID != 2 {
+			t.Errorf("Expected ID to be copied from Code, but got %d", dest.ID)
+		}
+	})
+}
+
+func TestCopyFromAliasConflict(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Src  interface{}
+		Dest interface{}
+	}{
+		{
+			Name: "MultipleDestinations",
+			Src:  &struct{ Code int }{Code: 1},
+			Dest: &struct {
+				A int `kopcup-alias:"Code"`
+				B int `kopcup-alias:"Code"`
+			}{},
+		},
+		{
+			Name: "SourceAndDestinationDisagree",
+			Src: &struct {
+				Code int `kopcup-alias:"A"`
+			}{Code: 1},
+			Dest: &struct {
+				A int
+				B int `kopcup-alias:"Code"`
+			}{},
+		},
+		{
+			Name: "DestinationClaimsAnother",
+			Src: &struct {
+				Code  int `kopcup-alias:"A"`
+				Other int
+			}{Code: 1},
+			Dest: &struct {
+				A int `kopcup-alias:"Other"`
+			}{},
+		},
+		{
+			Name: "SameDestination",
+			Src: &struct {
+				Title int
+				Code  int `kopcup-alias:"Title"`
+			}{Title: 1, Code: 2},
+			Dest: &struct{ Code, Title int }{},
+		},
+		{
+			Name: "SameDestinationByJSON",
+			Src: &struct {
+				Title int
+				Code  int `json:"Title"`
+			}{Title: 1, Code: 2},
+			Dest: &struct{ Title int }{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := CopyFrom(tc.Dest, tc.Src)
+			var fe *FieldError
+			if !errors.Is(err, ErrAliasConflict) || !errors.As(err, &fe) || fe.Path != "Code" {
+				t.Errorf("Expected ErrAliasConflict for Code, but got %v", err)
+			}
+		})
+	}
+}
+
+type FlatRow struct {
+	Name    string
+	City    string `kopcup-alias:"Address.City"`
+	Zip     string `kopcup-alias:"Address.Zip"`
+	Country string `kopcup-alias:"Address.Country.Name"`
+}
+
+type NestedCountry struct {
+	Name string
+}
+
+type NestedAddress struct {
+	City    string
+	Zip     string
+	Country *NestedCountry
+}
+
+type NestedResponse struct {
+	Name    string
+	Address *NestedAddress
+}
+
+func TestCopyFromDottedAlias(t *testing.T) {
+	row := FlatRow{Name: "taro", City: "Tokyo", Zip: "100", Country: "Japan"}
+
+	var resp NestedResponse
+	if err := CopyFrom(&resp, &row); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	expected := NestedResponse{Name: "taro", Address: &NestedAddress{City: "Tokyo", Zip: "100", Country: &NestedCountry{Name: "Japan"}}}
+	if !reflect.DeepEqual(resp, expected) {
+		t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", resp, expected)
+	}
+
+	var back FlatRow
+	if err := CopyFrom(&back, &resp, WithStrict()); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if back != row {
+		t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", back, row)
+	}
+
+	t.Run("NilIntermediate", func(t *testing.T) {
+		back := FlatRow{City: "keep"}
+		if err := CopyFrom(&back, &NestedResponse{Name: "jiro"}); err != nil {
+			t.Fatalf("CopyFrom failed: %v", err)
+		}
+		if back.Name != "jiro" || back.City != "keep" {
+			t.Errorf("Unexpected result. Got: %+v", back)
+		}
+	})
+
+	t.Run("SkipDoesNotAllocate", func(t *testing.T) {
+		var resp NestedResponse
+		if err := CopyFrom(&resp, &FlatRow{Name: "jiro"}, WithMerge(MergeNonZero)); err != nil {
+			t.Fatalf("CopyFrom failed: %v", err)
+		}
+		if resp.Address != nil {
+			t.Errorf("Expected Address to stay nil, but got %+v", resp.Address)
+		}
+	})
+
+	t.Run("Unresolved", func(t *testing.T) {
+		src := struct {
+			City string `kopcup-alias:"Address.Town"`
+		}{City: "Tokyo"}
+		if err := CopyFrom(&NestedResponse{}, &src, WithStrict()); !errors.Is(err, ErrUnmatched) {
+			t.Errorf("Expected ErrUnmatched, but got %v", err)
+		}
+	})
+}
+
+func TestFieldAlias(t *testing.T) {
+	keys := []string{"kopcup-alias", "json", "db"}
+	testCases := []struct {
+		Name     string
+		Field    reflect.StructField
+		Expected string
+	}{
+		{Name: "NoTag", Field: reflect.StructField{Name: "A"}, Expected: ""},
+		{Name: "KopcupAlias", Field: reflect.StructField{Name: "A", Tag: `kopcup-alias:"a" json:"b"`}, Expected: "a"},
+		{Name: "JSON", Field: reflect.StructField{Name: "A", Tag: `json:"user_id,omitempty" db:"uid"`}, Expected: "user_id"},
+		{Name: "JSONOptionsOnly", Field: reflect.StructField{Name: "A", Tag: `json:",omitempty" db:"uid"`}, Expected: "uid"},
+		{Name: "JSONIgnored", Field: reflect.StructField{Name: "A", Tag: `json:"-" db:"uid"`}, Expected: "uid"},
+		{Name: "DB", Field: reflect.StructField{Name: "A", Tag: `db:"user_id"`}, Expected: "user_id"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			if alias := fieldAlias(tc.Field, keys); alias != tc.Expected {
+				t.Errorf("Unexpected result. Got: %q, Expected: %q", alias, tc.Expected)
+			}
+		})
+	}
+}
+
+type TagRecord struct {
+	UserId   int    `db:"user_id"`
+	Name     string `db:"user_name"`
+	Email    string `json:"email,omitempty" db:"mail"`
+	Nickname string `kopcup-alias:"DisplayName" json:"nickname"`
+}
+
+type TagResponse struct {
+	UserID      int    `json:"user_id"`
+	UserName    string `json:"user_name,omitempty"`
+	Mail        string `json:"mail"`
+	DisplayName string `json:"display_name"`
+}
+
+func TestCopyFromTagKeys(t *testing.T) {
+	src := TagRecord{UserId: 1, Name: "taro", Email: "taro@example.com", Nickname: "tarochan"}
+
+	testCases := []struct {
+		Name     string
+		Options  []Option
+		Expected TagResponse
+	}{
+		{
+			// Email は json の名前 "email" が優先されるため db の "mail" では対応しない
+			Name:     "Default",
+			Expected: TagResponse{UserID: 1, UserName: "taro", DisplayName: "tarochan"},
+		},
+		{
+			Name:     "DBFirst",
+			Options:  []Option{WithTagKeys("db", "json")},
+			Expected: TagResponse{UserID: 1, UserName: "taro", Mail: "taro@example.com"},
+		},
+		{
+			Name:     "FieldNameOnly",
+			Options:  []Option{WithTagKeys()},
+			Expected: TagResponse{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var dest TagResponse
+			if err := CopyFrom(&dest, &src, tc.Options...); err != nil {
+				t.Fatalf("CopyFrom failed: %v", err)
+			}
+			if dest != tc.Expected {
+				t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", dest, tc.Expected)
+			}
+		})
+	}
+}
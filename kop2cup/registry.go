@@ -0,0 +1,105 @@
+package kop2cup
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+/*
+独自の変換関数です。
+  - src コピー元の値
+  - 戻り値はコピー先の型、またはコピー先の型へ Convert できる型の値
+*/
+type ConverterFunc func(src reflect.Value) (reflect.Value, error)
+
+type typePair struct {
+	src  reflect.Type
+	dest reflect.Type
+}
+
+/*
+コピー元とコピー先の型の組み合わせごとに変換関数を保持します。
+登録された変換関数は組み込みの変換より優先されます。
+*/
+type Registry struct {
+	mu         sync.RWMutex
+	converters map[typePair]ConverterFunc
+}
+
+/*
+全てのコピーで参照される変換関数の登録先です。
+*/
+var DefaultRegistry = NewRegistry()
+
+func NewRegistry() *Registry {
+	return &Registry{converters: map[typePair]ConverterFunc{}}
+}
+
+/*
+srcType から destType への変換関数を登録します。同じ組み合わせは上書きします。
+*/
+func (r *Registry) Register(srcType reflect.Type, destType reflect.Type, fn ConverterFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.converters[typePair{src: srcType, dest: destType}] = fn
+}
+
+/*
+S から D への変換関数を型を指定して登録します。
+*/
+func RegisterFunc[S, D any](r *Registry, fn func(S) (D, error)) {
+	srcType := reflect.TypeOf((*S)(nil)).Elem()
+	destType := reflect.TypeOf((*D)(nil)).Elem()
+	r.Register(srcType, destType, func(src reflect.Value) (reflect.Value, error) {
+		d, err := fn(src.Interface().(S))
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(&d).Elem(), nil
+	})
+}
+
+/*
+r に登録された変換関数を優先し、DefaultRegistry と組み込みの変換で CopyFrom と同様にコピーします。
+*/
+func (r *Registry) CopyFrom(dest interface{}, src interface{}, opts ...Option) error {
+	c, err := newConfig(opts...)
+	if err != nil {
+		return err
+	}
+	c.registry = r
+	return copyFrom(dest, src, c)
+}
+
+func (r *Registry) lookup(srcType reflect.Type, destType reflect.Type) (ConverterFunc, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	fn, ok := r.converters[typePair{src: srcType, dest: destType}]
+	return fn, ok
+}
+
+/*
+登録された変換関数で変換します。該当する変換関数がなければ ok は false です。
+呼び出し時のレジストリ、DefaultRegistry の順に参照します。
+*/
+func (c *config) convertByRegistry(destType reflect.Type, srcField reflect.Value) (v reflect.Value, ok bool, err error) {
+	var fn ConverterFunc
+	if c.registry != nil {
+		fn, ok = c.registry.lookup(srcField.Type(), destType)
+	}
+	if !ok {
+		fn, ok = DefaultRegistry.lookup(srcField.Type(), destType)
+	}
+	if !ok || !srcField.CanInterface() {
+		return reflect.Value{}, false, nil
+	}
+	v, err = fn(srcField)
+	if err != nil {
+		return reflect.Value{}, true, err
+	}
+	if !v.IsValid() || !v.Type().ConvertibleTo(destType) {
+		return reflect.Value{}, true, fmt.Errorf("%w: converter returned %v for %s", ErrUnsupported, v, destType)
+	}
+	return v.Convert(destType), true, nil
+}
@@ -0,0 +1,158 @@
+package kop2cup
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+type Status int
+
+const (
+	StatusUnknown Status = iota
+	StatusActive
+	StatusClosed
+)
+
+var errInvalidStatus = errors.New("invalid status")
+
+func parseMoney(s string) (Money, error) {
+	var m Money
+	if _, err := fmt.Sscanf(s, "%d %s", &m.Amount, &m.Currency); err != nil {
+		return Money{}, err
+	}
+	return m, nil
+}
+
+func parseStatus(s string) (Status, error) {
+	switch strings.ToLower(s) {
+	case "active":
+		return StatusActive, nil
+	case "closed":
+		return StatusClosed, nil
+	}
+	return StatusUnknown, errInvalidStatus
+}
+
+type SourceRegistry struct {
+	Price   string
+	Prices  []string
+	Deposit *string
+	Status  string
+}
+
+type DestinationRegistry struct {
+	Price   Money
+	Prices  []Money
+	Deposit *Money
+	Status  Status
+}
+
+func TestRegistryCopyFrom(t *testing.T) {
+	r := NewRegistry()
+	RegisterFunc(r, parseMoney)
+	RegisterFunc(r, parseStatus)
+
+	deposit := "500 USD"
+	src := SourceRegistry{Price: "100 JPY", Prices: []string{"1 JPY", "2 EUR"}, Deposit: &deposit, Status: "Active"}
+	dest := DestinationRegistry{}
+
+	if err := r.CopyFrom(&dest, &src); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+
+	expected := DestinationRegistry{
+		Price:   Money{Amount: 100, Currency: "JPY"},
+		Prices:  []Money{{Amount: 1, Currency: "JPY"}, {Amount: 2, Currency: "EUR"}},
+		Deposit: &Money{Amount: 500, Currency: "USD"},
+		Status:  StatusActive,
+	}
+	if !reflect.DeepEqual(dest, expected) {
+		t.Errorf("Unexpected result. \n      Got: %+v\n Expected: %+v", dest, expected)
+	}
+}
+
+func TestRegistryError(t *testing.T) {
+	r := NewRegistry()
+	RegisterFunc(r, parseStatus)
+
+	dest := DestinationRegistry{}
+	err := r.CopyFrom(&dest, &struct{ Status string }{Status: "deleted"})
+
+	var fe *FieldError
+	if !errors.As(err, &fe) || fe.Path != "Status" {
+		t.Fatalf("Expected a FieldError for Status, but got: %v", err)
+	}
+	if !errors.Is(err, errInvalidStatus) {
+		t.Errorf("Unexpected cause. Got: %v, Expected: %v", fe.Err, errInvalidStatus)
+	}
+}
+
+func TestRegistryOverride(t *testing.T) {
+	key := typePair{src: reflect.TypeOf(""), dest: reflect.TypeOf(StatusUnknown)}
+	t.Cleanup(func() {
+		DefaultRegistry.mu.Lock()
+		defer DefaultRegistry.mu.Unlock()
+		delete(DefaultRegistry.converters, key)
+	})
+	RegisterFunc(DefaultRegistry, func(s string) (Status, error) {
+		return StatusClosed, nil
+	})
+
+	testCases := []struct {
+		Name     string
+		Copy     func(dest interface{}, src interface{}) error
+		Expected Status
+	}{
+		{
+			Name:     "Global",
+			Copy:     func(dest interface{}, src interface{}) error { return CopyFrom(dest, src) },
+			Expected: StatusClosed,
+		},
+		{
+			Name: "PerCall",
+			Copy: func(dest interface{}, src interface{}) error {
+				r := NewRegistry()
+				RegisterFunc(r, parseStatus)
+				return r.CopyFrom(dest, src)
+			},
+			Expected: StatusActive,
+		},
+		{
+			Name:     "FallbackToGlobal",
+			Copy:     func(dest interface{}, src interface{}) error { return NewRegistry().CopyFrom(dest, src) },
+			Expected: StatusClosed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			dest := struct{ Status Status }{}
+			if err := tc.Copy(&dest, &struct{ Status string }{Status: "active"}); err != nil {
+				t.Fatalf("CopyFrom failed: %v", err)
+			}
+			if dest.Status != tc.Expected {
+				t.Errorf("Unexpected result. Got: %v, Expected: %v", dest.Status, tc.Expected)
+			}
+		})
+	}
+}
+
+func TestRegisterInvalidResult(t *testing.T) {
+	r := NewRegistry()
+	r.Register(reflect.TypeOf(""), reflect.TypeOf(0), func(src reflect.Value) (reflect.Value, error) {
+		return reflect.ValueOf(src.String()), nil
+	})
+
+	dest := struct{ Value int }{}
+	if err := r.CopyFrom(&dest, &struct{ Value string }{Value: "1"}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Unexpected error. Got: %v, Expected: %v", err, ErrUnsupported)
+	}
+}
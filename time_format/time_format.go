@@ -2,6 +2,9 @@ package timeFormat
 
 import (
 	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -25,13 +28,120 @@ func (t *TimeFormat) String() string {
 	return string(*t)
 }
 
+/*
+組み込みの定数の名前と値です。Register で上書きできません。
+*/
+var builtinNames = map[string]TimeFormat{
+	"RFC3339":          RFC3339,
+	"RFC3339Nano":      RFC3339Nano,
+	"RFC3339B":         RFC3339B,
+	"RFC3339BNano":     RFC3339BNano,
+	"RFC3339Block":     RFC3339Block,
+	"RFC3339BlockNano": RFC3339BlockNano,
+	"DateTime":         DateTime,
+	"DateOnlyA":        DateOnlyA,
+	"DateOnlyB":        DateOnlyB,
+	"DateOnlyBlock":    DateOnlyBlock,
+	"TimeOnlyA":        TimeOnlyA,
+	"WarekiKanji":      WarekiKanji,
+	"WarekiLetter":     WarekiLetter,
+}
+
+/*
+名前付きで登録された日付フォーマットです。組み込みの定数は builtinNames から参照します。
+*/
+var registry = struct {
+	mu      sync.RWMutex
+	layouts map[string]TimeFormat
+}{layouts: map[string]TimeFormat{}}
+
+/*
+日付フォーマットを名前付きで登録します。
+  - name kopcup-dateformat 等で指定する名前（組み込みの定数名は上書き不可）
+  - layout Go のレイアウト文字列（登録時に検証し、不正な場合はエラー）
+  - 同じ名前は上書き
+*/
+func Register(name string, layout string) (TimeFormat, error) {
+	if name == "" {
+		return TimeFormat("invalid"), errors.New("convert error: empty time format name")
+	}
+	if _, ok := isValueInTimeFormat(TimeFormat(layout)); !ok && !isValidLayout(layout) {
+		return TimeFormat("invalid"), fmt.Errorf("convert error: invalid layout %q", layout)
+	}
+
+	if _, builtin := builtinNames[name]; builtin {
+		return TimeFormat("invalid"), fmt.Errorf("convert error: %s is a built-in time format", name)
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.layouts[name] = TimeFormat(layout)
+	return TimeFormat(layout), nil
+}
+
+/*
+登録された名前から日付フォーマットを返します。
+*/
+func Lookup(name string) (TimeFormat, bool) {
+	if tfmt, ok := builtinNames[name]; ok {
+		return tfmt, true
+	}
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	tfmt, ok := registry.layouts[name]
+	return tfmt, ok
+}
+
+/*
+文字列を日付フォーマットに変換します。
+組み込みの定数の値、登録された名前、Go のレイアウト文字列の順に判定します。
+*/
 func StrToTimeFormat(str string) (TimeFormat, error) {
 	if tfmt, ok := isValueInTimeFormat(TimeFormat(str)); ok {
 		return *tfmt, nil
 	}
+	if tfmt, ok := Lookup(str); ok {
+		return tfmt, nil
+	}
+	if isValidLayout(str) {
+		return TimeFormat(str), nil
+	}
 	return TimeFormat("invalid"), errors.New("convert error: not defined")
 }
 
+/*
+"|" で区切った文字列を日付フォーマットの一覧に変換します。
+各要素は StrToTimeFormat と同じ規則で判定し、順序を保ちます。
+*/
+func StrToTimeFormats(str string) ([]TimeFormat, error) {
+	parts := strings.Split(str, "|")
+	tfmts := make([]TimeFormat, len(parts))
+	for i, part := range parts {
+		tfmt, err := StrToTimeFormat(part)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", err, part)
+		}
+		tfmts[i] = tfmt
+	}
+	return tfmts, nil
+}
+
+/*
+Go のレイアウト文字列として有効であれば true を返します。
+  - 日付・時刻の要素を1つ以上含む（異なる2つの時刻の書式化結果が異なる）
+  - 書式化した文字列を同じレイアウトで解析できる
+*/
+func isValidLayout(layout string) bool {
+	t1 := time.Date(2001, 2, 3, 4, 5, 6, 7000000, time.FixedZone("", 9*60*60))
+	t2 := time.Date(2012, 11, 22, 16, 35, 46, 890000000, time.UTC)
+	s := t1.Format(layout)
+	if s == t2.Format(layout) {
+		return false
+	}
+	_, err := time.Parse(layout, s)
+	return err == nil
+}
+
 func isValueInTimeFormat(tfmt TimeFormat) (*TimeFormat, bool) {
 	switch tfmt {
 	case RFC3339,
@@ -44,7 +154,9 @@ func isValueInTimeFormat(tfmt TimeFormat) (*TimeFormat, bool) {
 		DateOnlyA,
 		DateOnlyB,
 		DateOnlyBlock,
-		TimeOnlyA:
+		TimeOnlyA,
+		WarekiKanji,
+		WarekiLetter:
 		return &tfmt, true
 	}
 	return nil, false
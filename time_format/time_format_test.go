@@ -1,6 +1,7 @@
 package timeFormat
 
 import (
+	"reflect"
 	"testing"
 	"time"
 )
@@ -55,7 +56,14 @@ func TestStrToTimeFormat(t *testing.T) {
 		{Input: "2006/01/02", Expected: DateOnlyB},
 		{Input: "20060102", Expected: DateOnlyBlock},
 		{Input: "2006-01-02 15:04:05", Expected: DateTime},
+		{Input: "RFC3339", Expected: RFC3339},
+		{Input: "DateOnlyB", Expected: DateOnlyB},
+		{Input: "GGGGy年M月d日", Expected: WarekiKanji},
+		{Input: "WarekiLetter", Expected: WarekiLetter},
+		{Input: "2006/01/02 15:04", Expected: TimeFormat("2006/01/02 15:04")},
+		{Input: "2006-01-02T15:04:05.000", Expected: TimeFormat("2006-01-02T15:04:05.000")},
 		{Input: "invalid", ShouldFail: true},
+		{Input: "", ShouldFail: true},
 	}
 
 	for _, tc := range testCases {
@@ -97,6 +105,8 @@ func TestIsValueInTimeFormat(t *testing.T) {
 		{Input: DateOnlyB, Expected: true},
 		{Input: DateOnlyBlock, Expected: true},
 		{Input: TimeOnlyA, Expected: true},
+		{Input: WarekiKanji, Expected: true},
+		{Input: WarekiLetter, Expected: true},
 		{Input: TimeFormat("invalid"), Expected: false},
 	}
 
@@ -114,3 +124,85 @@ func TestIsValueInTimeFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestRegister(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		Layout     string
+		ShouldFail bool
+	}{
+		{Name: "Minute", Layout: "2006/01/02 15:04"},
+		{Name: "Milli", Layout: "2006-01-02T15:04:05.000"},
+		{Name: "Builtin", Layout: string(DateOnlyA)},
+		{Name: "MyDate", Layout: "2006-01-02"},
+		{Name: "MyDate", Layout: "2006/01/02 15:04"},
+		{Name: "Invalid", Layout: "invalid", ShouldFail: true},
+		{Name: "Empty", Layout: "", ShouldFail: true},
+		{Name: "", Layout: "2006", ShouldFail: true},
+		{Name: "RFC3339", Layout: "2006", ShouldFail: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			result, err := Register(tc.Name, tc.Layout)
+
+			if tc.ShouldFail {
+				if err == nil {
+					t.Error("Expected an error, but got none.")
+				}
+				if _, ok := Lookup(tc.Name); ok && tc.Name != "RFC3339" {
+					t.Errorf("Expected %q not to be registered", tc.Name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != TimeFormat(tc.Layout) {
+				t.Errorf("Unexpected result. Got: %v, Expected: %v", result, tc.Layout)
+			}
+			if tfmt, err := StrToTimeFormat(tc.Name); err != nil || tfmt != result {
+				t.Errorf("Unexpected lookup. Got: %v (%v), Expected: %v", tfmt, err, result)
+			}
+		})
+	}
+
+	if tfmt, _ := Lookup("MyDate"); tfmt != "2006/01/02 15:04" {
+		t.Errorf("Expected MyDate to be overwritten, but got %v", tfmt)
+	}
+	if tfmt, _ := Lookup("RFC3339"); tfmt != RFC3339 {
+		t.Errorf("Built-in format was overwritten: %v", tfmt)
+	}
+}
+
+func TestStrToTimeFormats(t *testing.T) {
+	testCases := []struct {
+		Input      string
+		Expected   []TimeFormat
+		ShouldFail bool
+	}{
+		{Input: "2006/01/02", Expected: []TimeFormat{DateOnlyB}},
+		{Input: "2006/01/02|20060102|RFC3339", Expected: []TimeFormat{DateOnlyB, DateOnlyBlock, RFC3339}},
+		{Input: "2006/01/02|invalid", ShouldFail: true},
+		{Input: "2006/01/02|", ShouldFail: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Input, func(t *testing.T) {
+			result, err := StrToTimeFormats(tc.Input)
+
+			if tc.ShouldFail {
+				if err == nil {
+					t.Error("Expected an error, but got none.")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tc.Expected) {
+				t.Errorf("Unexpected result. Got: %v, Expected: %v", result, tc.Expected)
+			}
+		})
+	}
+}
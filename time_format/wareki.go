@@ -0,0 +1,132 @@
+package timeFormat
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+/*
+和暦の日付フォーマットです。Go のレイアウトでは表現できないため Format / Parse で個別に処理します。
+  - WarekiKanji 例: "令和6年4月1日"（1年は "元年"）
+  - WarekiLetter 例: "R06.04.01"（元号の頭文字と2桁の年月日）
+*/
+const (
+	WarekiKanji  TimeFormat = "GGGGy年M月d日"
+	WarekiLetter TimeFormat = "Gyy.MM.dd"
+)
+
+/*
+元号です。
+  - name 漢字表記
+  - letter 頭文字
+  - start 開始日
+*/
+type era struct {
+	name   string
+	letter string
+	start  time.Time
+}
+
+/*
+明治から令和までの元号です（開始日の昇順）。
+*/
+var eras = []era{
+	{name: "明治", letter: "M", start: time.Date(1868, 1, 1, 0, 0, 0, 0, time.UTC)},
+	{name: "大正", letter: "T", start: time.Date(1912, 7, 30, 0, 0, 0, 0, time.UTC)},
+	{name: "昭和", letter: "S", start: time.Date(1926, 12, 25, 0, 0, 0, 0, time.UTC)},
+	{name: "平成", letter: "H", start: time.Date(1989, 1, 8, 0, 0, 0, 0, time.UTC)},
+	{name: "令和", letter: "R", start: time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC)},
+}
+
+var (
+	warekiKanjiPattern  = regexp.MustCompile(`^(明治|大正|昭和|平成|令和)(元|\d{1,2})年(\d{1,2})月(\d{1,2})日$`)
+	warekiLetterPattern = regexp.MustCompile(`^([MTSHR])(\d{1,2})\.(\d{1,2})\.(\d{1,2})$`)
+)
+
+/*
+日付フォーマットで書式化します。和暦の場合、明治より前の日付はエラーです。
+*/
+func (t *TimeFormat) Format(tm time.Time) (string, error) {
+	if *t != WarekiKanji && *t != WarekiLetter {
+		return tm.Format(t.String()), nil
+	}
+	e, year, ok := eraOf(tm)
+	if !ok {
+		return "", fmt.Errorf("convert error: %s is before %s", tm.Format(time.DateOnly), eras[0].name)
+	}
+	if *t == WarekiLetter {
+		return fmt.Sprintf("%s%02d.%02d.%02d", e.letter, year, tm.Month(), tm.Day()), nil
+	}
+	y := strconv.Itoa(year)
+	if year == 1 {
+		y = "元"
+	}
+	return fmt.Sprintf("%s%s年%d月%d日", e.name, y, tm.Month(), tm.Day()), nil
+}
+
+/*
+日付フォーマットで value を解析し、loc の時刻として返します。
+和暦の場合、元号の期間外の日付（例: "平成32年"）はエラーです。
+*/
+func (t *TimeFormat) Parse(value string, loc *time.Location) (time.Time, error) {
+	switch *t {
+	case WarekiKanji:
+		m := warekiKanjiPattern.FindStringSubmatch(value)
+		if m == nil {
+			return time.Time{}, fmt.Errorf("convert error: %q does not match %s", value, *t)
+		}
+		if m[2] == "元" {
+			m[2] = "1"
+		}
+		return warekiDate(value, m[1], m[2], m[3], m[4], loc)
+	case WarekiLetter:
+		m := warekiLetterPattern.FindStringSubmatch(value)
+		if m == nil {
+			return time.Time{}, fmt.Errorf("convert error: %q does not match %s", value, *t)
+		}
+		return warekiDate(value, m[1], m[2], m[3], m[4], loc)
+	}
+	return time.ParseInLocation(t.String(), value, loc)
+}
+
+/*
+tm の日付の元号と和暦の年を返します。明治より前の場合 ok は false です。
+*/
+func eraOf(tm time.Time) (e era, year int, ok bool) {
+	y, m, d := tm.Date()
+	date := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	for i := len(eras) - 1; i >= 0; i-- {
+		if !date.Before(eras[i].start) {
+			return eras[i], y - eras[i].start.Year() + 1, true
+		}
+	}
+	return era{}, 0, false
+}
+
+/*
+元号（漢字または頭文字）と和暦の年月日から日付を作成します。
+*/
+func warekiDate(value string, name string, year string, month string, day string, loc *time.Location) (time.Time, error) {
+	idx := -1
+	for i, e := range eras {
+		if e.name == name || e.letter == name {
+			idx = i
+		}
+	}
+	y, _ := strconv.Atoi(year)
+	mo, _ := strconv.Atoi(month)
+	d, _ := strconv.Atoi(day)
+	if idx < 0 || y < 1 {
+		return time.Time{}, fmt.Errorf("convert error: invalid wareki date %q", value)
+	}
+
+	e := eras[idx]
+	date := time.Date(e.start.Year()+y-1, time.Month(mo), d, 0, 0, 0, 0, time.UTC)
+	if date.Month() != time.Month(mo) || date.Day() != d || date.Before(e.start) ||
+		(idx+1 < len(eras) && !date.Before(eras[idx+1].start)) {
+		return time.Time{}, fmt.Errorf("convert error: invalid wareki date %q", value)
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc), nil
+}
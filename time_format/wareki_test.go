@@ -0,0 +1,91 @@
+package timeFormat
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWarekiFormat(t *testing.T) {
+	testCases := []struct {
+		Input      time.Time
+		Format     TimeFormat
+		Expected   string
+		ShouldFail bool
+	}{
+		{Input: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), Format: WarekiKanji, Expected: "令和6年4月1日"},
+		{Input: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), Format: WarekiLetter, Expected: "R06.04.01"},
+		{Input: time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC), Format: WarekiKanji, Expected: "令和元年5月1日"},
+		{Input: time.Date(2019, 4, 30, 0, 0, 0, 0, time.UTC), Format: WarekiKanji, Expected: "平成31年4月30日"},
+		{Input: time.Date(1989, 1, 7, 0, 0, 0, 0, time.UTC), Format: WarekiLetter, Expected: "S64.01.07"},
+		{Input: time.Date(1989, 1, 8, 0, 0, 0, 0, time.UTC), Format: WarekiKanji, Expected: "平成元年1月8日"},
+		{Input: time.Date(1926, 12, 25, 0, 0, 0, 0, time.UTC), Format: WarekiLetter, Expected: "S01.12.25"},
+		{Input: time.Date(1912, 7, 30, 0, 0, 0, 0, time.UTC), Format: WarekiKanji, Expected: "大正元年7月30日"},
+		{Input: time.Date(1868, 1, 1, 0, 0, 0, 0, time.UTC), Format: WarekiKanji, Expected: "明治元年1月1日"},
+		{Input: time.Date(1867, 12, 31, 0, 0, 0, 0, time.UTC), Format: WarekiKanji, ShouldFail: true},
+		{Input: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), Format: DateOnlyB, Expected: "2024/04/01"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Expected, func(t *testing.T) {
+			result, err := tc.Format.Format(tc.Input)
+
+			if tc.ShouldFail {
+				if err == nil {
+					t.Error("Expected an error, but got none.")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != tc.Expected {
+				t.Errorf("Unexpected result. Got: %v, Expected: %v", result, tc.Expected)
+			}
+		})
+	}
+}
+
+func TestWarekiParse(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	testCases := []struct {
+		Input      string
+		Format     TimeFormat
+		Expected   time.Time
+		ShouldFail bool
+	}{
+		{Input: "令和6年4月1日", Format: WarekiKanji, Expected: time.Date(2024, 4, 1, 0, 0, 0, 0, jst)},
+		{Input: "令和元年5月1日", Format: WarekiKanji, Expected: time.Date(2019, 5, 1, 0, 0, 0, 0, jst)},
+		{Input: "令和1年5月1日", Format: WarekiKanji, Expected: time.Date(2019, 5, 1, 0, 0, 0, 0, jst)},
+		{Input: "平成31年04月30日", Format: WarekiKanji, Expected: time.Date(2019, 4, 30, 0, 0, 0, 0, jst)},
+		{Input: "明治45年7月29日", Format: WarekiKanji, Expected: time.Date(1912, 7, 29, 0, 0, 0, 0, jst)},
+		{Input: "R06.04.01", Format: WarekiLetter, Expected: time.Date(2024, 4, 1, 0, 0, 0, 0, jst)},
+		{Input: "S64.01.07", Format: WarekiLetter, Expected: time.Date(1989, 1, 7, 0, 0, 0, 0, jst)},
+		{Input: "T1.7.30", Format: WarekiLetter, Expected: time.Date(1912, 7, 30, 0, 0, 0, 0, jst)},
+		{Input: "平成32年1月1日", Format: WarekiKanji, ShouldFail: true},
+		{Input: "令和元年4月30日", Format: WarekiKanji, ShouldFail: true},
+		{Input: "令和6年2月30日", Format: WarekiKanji, ShouldFail: true},
+		{Input: "令和0年1月1日", Format: WarekiKanji, ShouldFail: true},
+		{Input: "X06.04.01", Format: WarekiLetter, ShouldFail: true},
+		{Input: "R06.04.01", Format: WarekiKanji, ShouldFail: true},
+		{Input: "2024/04/01", Format: DateOnlyB, Expected: time.Date(2024, 4, 1, 0, 0, 0, 0, jst)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Input, func(t *testing.T) {
+			result, err := tc.Format.Parse(tc.Input, jst)
+
+			if tc.ShouldFail {
+				if err == nil {
+					t.Errorf("Expected an error, but got %v", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !result.Equal(tc.Expected) || result.Location() != jst {
+				t.Errorf("Unexpected result. Got: %v, Expected: %v", result, tc.Expected)
+			}
+		})
+	}
+}